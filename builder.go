@@ -14,11 +14,24 @@
 //
 package swag
 
-import "github.com/savaki/swag/swagger"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/savaki/swag/endpoint"
+	"github.com/savaki/swag/swagger"
+)
 
 // Builder uses the builder pattern to generate a swagger definition
 type Builder struct {
 	API *swagger.API
+
+	endpoints   []*swagger.Endpoint
+	parameters  map[string]swagger.Parameter
+	responses   map[string]swagger.Response
+	definitions []interface{}
+	validate    bool
+	errs        swagger.Errors
 }
 
 // Option provides configuration options to the swagger api builder
@@ -125,12 +138,69 @@ func Host(v string) Option {
 	}
 }
 
-// Endpoints allows the endpoints to be added dynamically to the Api
+// Endpoints allows the endpoints to be added dynamically to the Api; endpoints are added once all other options have
+// been applied
 func Endpoints(endpoints ...*swagger.Endpoint) Option {
 	return func(builder *Builder) {
-		for _, e := range endpoints {
-			builder.API.AddEndpoint(e)
+		builder.endpoints = append(builder.endpoints, endpoints...)
+	}
+}
+
+// Parameter adds a parameter, named name, that endpoints may reference rather than repeat via endpoint.ParameterRef
+// e.g. swag.Parameter("limit", swagger.Parameter{In: "query", Name: "limit", Type: "integer"}).  swagger.MakeParameter
+// may be used to derive the parameter from a go type
+func Parameter(name string, p swagger.Parameter) Option {
+	return func(builder *Builder) {
+		if builder.parameters == nil {
+			builder.parameters = map[string]swagger.Parameter{}
+		}
+		builder.parameters[name] = p
+	}
+}
+
+// Response adds a response, named name, that endpoints may reference rather than repeat via endpoint.ResponseRef.
+// prototype describes the body of the response and may be nil for responses without one
+func Response(name string, prototype interface{}, description string, opts ...endpoint.ResponseOption) Option {
+	return func(builder *Builder) {
+		if builder.responses == nil {
+			builder.responses = map[string]swagger.Response{}
+		}
+
+		r := swagger.Response{
+			Description: description,
 		}
+		if prototype != nil {
+			r.Schema = swagger.MakeSchema(prototype)
+		}
+
+		for _, opt := range opts {
+			opt.Apply(&r)
+		}
+
+		builder.responses[name] = r
+	}
+}
+
+// Definitions defines the go types of the prototypes whether or not they are used by any endpoint
+func Definitions(prototypes ...interface{}) Option {
+	return func(builder *Builder) {
+		builder.definitions = append(builder.definitions, prototypes...)
+	}
+}
+
+// InferRequired marks the fields of definitions tagged validate:"required", and the non-pointer fields unless their
+// json tag includes omitempty, as required.  Individual fields may opt out via the required:"false" tag
+func InferRequired() Option {
+	return func(builder *Builder) {
+		builder.API.SchemaOptions.InferRequired = true
+	}
+}
+
+// InferNullable marks the pointer fields of definitions as nullable.  Individual fields may opt out via the
+// nullable:"false" tag
+func InferNullable() Option {
+	return func(builder *Builder) {
+		builder.API.SchemaOptions.InferNullable = true
 	}
 }
 
@@ -147,6 +217,11 @@ func SecurityScheme(name string, options ...swagger.SecuritySchemeOption) Option
 			opt(&scheme)
 		}
 
+		if err := scheme.Err(); err != nil {
+			builder.errs = append(builder.errs, fmt.Errorf("security scheme, %v: %v", name, err))
+			return
+		}
+
 		builder.API.SecurityDefinitions[name] = scheme
 	}
 }
@@ -166,8 +241,58 @@ func Security(scheme string, scopes ...string) Option {
 	}
 }
 
-// New constructs a new api builder
+// Naming sets the strategy used to name definitions e.g. swagger.ShortNames; by default definitions are named after
+// the last element of the package path and the go type
+func Naming(strategy swagger.NamingStrategy) Option {
+	return func(builder *Builder) {
+		builder.API.SchemaOptions.Naming = strategy
+	}
+}
+
+// Comments describes definitions and their properties using the doc comments of their go types, e.g. those returned
+// by swagger.ParseComments.  Description tags take precedence
+func Comments(comments *swagger.Comments) Option {
+	return func(builder *Builder) {
+		builder.API.SchemaOptions.Comments = comments
+	}
+}
+
+// Extends declares that the type of the child prototype extends the type of the parent prototype; the child
+// definition is described as allOf the parent definition and the properties the child does not inherit
+func Extends(child, parent interface{}) Option {
+	return func(builder *Builder) {
+		builder.API.SchemaOptions.Extend(child, parent)
+	}
+}
+
+// Discriminator declares that the type of the base prototype is polymorphic.  The value of the property, propertyName,
+// identifies the concrete type using mapping, a map of values to prototypes; each mapped type extends base
+func Discriminator(base interface{}, propertyName string, mapping map[string]interface{}) Option {
+	return func(builder *Builder) {
+		builder.API.SchemaOptions.Discriminate(base, propertyName, mapping)
+	}
+}
+
+// Validate validates the api once it has been constructed; the swagger.ValidationErrors found are reported by New, or
+// returned by NewE, if any are errors rather than warnings
+func Validate() Option {
+	return func(builder *Builder) {
+		builder.validate = true
+	}
+}
+
+// New constructs a new api builder.  New panics if the api is invalid; see NewE
 func New(options ...Option) *swagger.API {
+	api, err := NewE(options...)
+	if err != nil {
+		panic(err)
+	}
+	return api
+}
+
+// NewE is the same as New, but returns the configuration errors accumulated from every option and endpoint rather than
+// panicking
+func NewE(options ...Option) (*swagger.API, error) {
 	b := &Builder{
 		API: &swagger.API{
 			BasePath: "/",
@@ -193,5 +318,43 @@ func New(options ...Option) *swagger.API {
 		opt(b)
 	}
 
-	return b.API
+	// parameters and responses are added in order of name so that definitions are named deterministically
+	names := make([]string, 0, len(b.parameters))
+	for name := range b.parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.API.AddParameter(name, b.parameters[name])
+	}
+
+	names = make([]string, 0, len(b.responses))
+	for name := range b.responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.API.AddResponse(name, b.responses[name])
+	}
+
+	if len(b.definitions) > 0 {
+		b.API.AddDefinitions(b.definitions...)
+	}
+
+	for _, e := range b.endpoints {
+		if err := b.API.AddEndpointE(e); err != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+
+	if b.validate {
+		if errs := b.API.Validate().Errors(); len(errs) > 0 {
+			b.errs = append(b.errs, errs)
+		}
+	}
+
+	if err := b.errs.Err(); err != nil {
+		return nil, err
+	}
+	return b.API, nil
 }
@@ -15,9 +15,13 @@
 package swag_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/savaki/swag"
+	"github.com/savaki/swag/endpoint"
 	"github.com/savaki/swag/swagger"
 	"github.com/stretchr/testify/assert"
 )
@@ -123,3 +127,254 @@ func TestSecurity(t *testing.T) {
 	assert.Len(t, api.Security.Requirements, 1)
 	assert.Contains(t, api.Security.Requirements[0], "basic")
 }
+
+type Login struct {
+	Username string  `json:"username"`
+	Password string  `json:"password"`
+	Remember bool    `json:"remember,omitempty"`
+	Token    *string `json:"token"`
+	Realm    string  `json:"realm" required:"false"`
+}
+
+func TestInferRequired(t *testing.T) {
+	e := endpoint.New("post", "/login", "login",
+		endpoint.Body(Login{}, "credentials", true),
+	)
+
+	api := swag.New(
+		swag.Endpoints(e),
+		swag.InferRequired(),
+	)
+	assert.True(t, api.SchemaOptions.InferRequired)
+	assert.Equal(t, []string{"username", "password"}, api.Definitions["swag_testLogin"].Required)
+
+	api = swag.New(
+		swag.Endpoints(e),
+	)
+	assert.Nil(t, api.Definitions["swag_testLogin"].Required)
+}
+
+func TestNaming(t *testing.T) {
+	post := endpoint.New("post", "/login", "login",
+		endpoint.Body(Login{}, "credentials", true),
+		endpoint.Response(http.StatusOK, Login{}, "ok"),
+	)
+
+	api := swag.New(
+		swag.Naming(swagger.ShortNames),
+		swag.Endpoints(post),
+	)
+	assert.Contains(t, api.Definitions, "Login")
+	login := api.Paths["/login"].Post
+	assert.Equal(t, "#/definitions/Login", login.Parameters[0].Schema.Ref)
+	assert.Equal(t, "#/definitions/Login", login.Responses["200"].Schema.Ref)
+
+	other := swag.New(
+		swag.Endpoints(post),
+	)
+	assert.Equal(t, "#/definitions/swag_testLogin", other.Paths["/login"].Post.Responses["200"].Schema.Ref)
+	assert.Equal(t, "#/definitions/Login", login.Responses["200"].Schema.Ref,
+		"expected each api to hold its own copy of the endpoint")
+	assert.Equal(t, "#/definitions/swag_testLogin", post.Responses["200"].Schema.Ref,
+		"expected the endpoint to be left unmodified")
+}
+
+func TestNamingCollision(t *testing.T) {
+	first := func() *swagger.Endpoint {
+		type User struct {
+			A string
+		}
+		return endpoint.New("get", "/a", "a", endpoint.Response(http.StatusOK, User{}, "ok"))
+	}()
+	second := func() *swagger.Endpoint {
+		type User struct {
+			B string
+		}
+		return endpoint.New("get", "/b", "b", endpoint.Response(http.StatusOK, User{}, "ok"))
+	}()
+
+	api := swag.New(
+		swag.Naming(swagger.ShortNames),
+		swag.Endpoints(first, second),
+	)
+	assert.Len(t, api.Definitions, 2)
+	assert.Equal(t, "#/definitions/User", api.Paths["/a"].Get.Responses["200"].Schema.Ref)
+	assert.Equal(t, "#/definitions/github.com.savaki.swag_test.User", api.Paths["/b"].Get.Responses["200"].Schema.Ref)
+	assert.Contains(t, api.Definitions["User"].Properties, "A")
+	assert.Contains(t, api.Definitions["github.com.savaki.swag_test.User"].Properties, "B")
+}
+
+type Pet struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type Cat struct {
+	Pet
+	Indoor bool `json:"indoor"`
+}
+
+type Dog struct {
+	Pet
+	Breed string `json:"breed"`
+}
+
+type Puppy struct {
+	Dog
+	Age int `json:"age"`
+}
+
+func TestDiscriminator(t *testing.T) {
+	e := endpoint.New("get", "/pets", "list pets",
+		endpoint.Response(http.StatusOK, []Pet{}, "ok"),
+	)
+
+	api := swag.New(
+		swag.Endpoints(e),
+		swag.Discriminator(Pet{}, "type", map[string]interface{}{
+			"cat": Cat{},
+			"dog": Dog{},
+		}),
+		swag.Extends(Puppy{}, Dog{}),
+	)
+	assert.Len(t, api.Definitions, 3)
+	assert.Equal(t, "type", api.Definitions["swag_testPet"].Discriminator)
+	assert.Equal(t, "#/definitions/swag_testPet", api.Definitions["swag_testCat"].AllOf[0].Ref)
+	assert.Equal(t, "#/definitions/swag_testPet", api.Definitions["swag_testDog"].AllOf[0].Ref)
+	assert.Equal(t, reflect.TypeOf(Dog{}), api.SchemaOptions.Extends[reflect.TypeOf(Puppy{})])
+}
+
+func TestValidate(t *testing.T) {
+	valid := endpoint.New("get", "/pets/{id}", "find pet",
+		endpoint.Path("id", "integer", "pet id", true),
+		endpoint.Tags("undeclared"),
+	)
+	assert.NotPanics(t, func() {
+		swag.New(swag.Validate(), swag.Endpoints(valid))
+	}, "expected warnings to be tolerated")
+
+	invalid := endpoint.New("get", "/pets/{id}", "find pet")
+	assert.NotPanics(t, func() {
+		swag.New(swag.Endpoints(invalid))
+	}, "expected validation to be opt-in")
+
+	defer func() {
+		errs, ok := recover().(swagger.ValidationErrors)
+		assert.True(t, ok)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, swagger.MissingPathParameter, errs[0].Code)
+	}()
+	swag.New(swag.Validate(), swag.Endpoints(invalid))
+}
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type Audit struct {
+	User string `json:"user"`
+}
+
+func TestReusable(t *testing.T) {
+	list := endpoint.New("get", "/pets/{id}", "find pet",
+		endpoint.ParameterRef("id"),
+		endpoint.ParameterRef("limit"),
+		endpoint.Response(http.StatusOK, Pet{}, "ok"),
+		endpoint.ResponseRef(http.StatusInternalServerError, "Error"),
+	)
+
+	api := swag.New(
+		swag.Parameter("id", swagger.Parameter{In: "path", Name: "id", Type: "integer", Required: true}),
+		swag.Parameter("limit", swagger.Parameter{In: "query", Name: "limit", Type: "integer"}),
+		swag.Response("Error", Error{}, "unexpected error",
+			endpoint.Header("X-Request-ID", "string", "", "request id"),
+		),
+		swag.Response("NotFound", nil, "not found"),
+		swag.Definitions(Audit{}),
+		swag.Naming(swagger.ShortNames),
+		swag.Validate(),
+		swag.Endpoints(list),
+	)
+
+	assert.Len(t, api.Parameters, 2)
+	assert.Equal(t, "#/definitions/Error", api.Responses["Error"].Schema.Ref, "expected options to apply to responses")
+	assert.Nil(t, api.Responses["NotFound"].Schema)
+	assert.Contains(t, api.Definitions, "Audit")
+
+	data, err := json.Marshal(api)
+	assert.Nil(t, err)
+
+	doc := struct {
+		Paths map[string]map[string]struct {
+			Parameters []map[string]interface{}          `json:"parameters"`
+			Responses  map[string]map[string]interface{} `json:"responses"`
+		} `json:"paths"`
+		Parameters map[string]map[string]interface{} `json:"parameters"`
+		Responses  map[string]map[string]interface{} `json:"responses"`
+	}{}
+	assert.Nil(t, json.Unmarshal(data, &doc))
+
+	get := doc.Paths["/pets/{id}"]["get"]
+	assert.Equal(t, []map[string]interface{}{
+		{"$ref": "#/parameters/id"},
+		{"$ref": "#/parameters/limit"},
+	}, get.Parameters)
+	assert.Equal(t, map[string]interface{}{"$ref": "#/responses/Error"}, get.Responses["500"])
+	assert.Equal(t, "limit", doc.Parameters["limit"]["name"])
+	assert.Equal(t, "unexpected error", doc.Responses["Error"]["description"])
+}
+
+func TestCookieParameterRef(t *testing.T) {
+	api := swag.New(
+		swag.Parameter("session", swagger.Parameter{In: "cookie", Name: "session", Type: "string"}),
+		swag.Endpoints(
+			endpoint.New("get", "/pets", "list pets",
+				endpoint.ParameterRef("session"),
+				endpoint.Query("limit", "integer", "max results", false),
+			),
+		),
+	)
+
+	data, err := json.Marshal(api)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "session", "expected references to cookie parameters to be omitted")
+	assert.Len(t, api.Paths["/pets"].Get.Parameters, 2, "expected the api to be left unmodified")
+
+	errs := api.Validate()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, swagger.SeverityWarning, errs[0].Severity)
+		assert.Equal(t, swagger.OmittedParameter, errs[0].Code)
+		assert.Equal(t, "#/paths/~1pets/get/parameters/0", errs[0].Location)
+	}
+}
+
+func TestNewE(t *testing.T) {
+	api, err := swag.NewE(swag.Title("pets"))
+	assert.Nil(t, err)
+	assert.Equal(t, "pets", api.Info.Title)
+
+	api, err = swag.NewE(
+		swag.Validate(),
+		swag.Endpoints(
+			&swagger.Endpoint{Method: "FETCH", Path: "/pets"},
+			endpoint.New("get", "/pets/{id}", "find pet"),
+		),
+	)
+	assert.Nil(t, api)
+	if errs, ok := err.(swagger.Errors); assert.True(t, ok, "expected every error to be reported") {
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], "invalid method, FETCH")
+		assert.IsType(t, swagger.ValidationErrors{}, errs[1])
+	}
+
+	assert.Panics(t, func() {
+		swag.New(swag.Endpoints(&swagger.Endpoint{Method: "FETCH", Path: "/pets"}))
+	})
+
+	api, err = swag.NewE(
+		swag.SecurityScheme("key", swagger.APIKeySecurity("api_key", "cookie")),
+	)
+	assert.Nil(t, api)
+	assert.EqualError(t, err, `security scheme, key: APIKeySecurity "in" parameter must be one of: "header" or "query"`)
+}
@@ -15,6 +15,7 @@
 package endpoint
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -26,6 +27,9 @@ import (
 // Builder uses the builder pattern to generate swagger endpoint definitions
 type Builder struct {
 	Endpoint *swagger.Endpoint
+
+	consumes bool // true once consumes has been set explicitly rather than defaulted
+	errs     swagger.Errors
 }
 
 // Option represents a functional option to customize the swagger endpoint
@@ -73,22 +77,189 @@ func Produces(v ...string) Option {
 func Consumes(v ...string) Option {
 	return func(b *Builder) {
 		b.Endpoint.Consumes = v
+		b.consumes = true
 	}
 }
 
-func parameter(p swagger.Parameter) Option {
+func parameter(p swagger.Parameter, options ...ParameterOption) Option {
 	return func(b *Builder) {
 		if b.Endpoint.Parameters == nil {
 			b.Endpoint.Parameters = []swagger.Parameter{}
 		}
 
-		b.Endpoint.Parameters = append(b.Endpoint.Parameters, p)
+		param := p
+		for _, opt := range options {
+			opt.Apply(&param)
+		}
+
+		if err := checkType(param); err != nil {
+			b.errs = append(b.errs, err)
+		}
+
+		b.Endpoint.Parameters = append(b.Endpoint.Parameters, param)
+	}
+}
+
+// parameterTypes lists the swagger types of non-body parameters and of their items
+var parameterTypes = map[string]bool{
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"array":   true,
+}
+
+// checkType returns an error if the type of the non-body parameter, p, or of its items is not a swagger type e.g. int
+// rather than integer, or is an array that does not describe its items
+func checkType(p swagger.Parameter) error {
+	if p.In == "body" || p.Ref != "" {
+		return nil
+	}
+
+	if !parameterTypes[p.Type] && !(p.Type == "file" && p.In == "formData") {
+		return fmt.Errorf("%v parameter, %v, has invalid type, %v", p.In, p.Name, p.Type)
+	}
+	if p.Type == "array" && p.Items == nil {
+		return fmt.Errorf("%v parameter, %v, is an array with no items; see Items", p.In, p.Name)
+	}
+
+	for items := p.Items; items != nil; items = items.Items {
+		if !parameterTypes[items.Type] {
+			return fmt.Errorf("%v parameter, %v, has invalid items type, %v", p.In, p.Name, items.Type)
+		}
+		if items.Type == "array" && items.Items == nil {
+			return fmt.Errorf("%v parameter, %v, has array items with no items", p.In, p.Name)
+		}
+	}
+
+	return nil
+}
+
+// ParameterOption allows non-body parameters to be documented as precisely as body schemas e.g. enums and constraints
+type ParameterOption func(p *swagger.Parameter)
+
+// Apply improves the readability of applied options
+func (o ParameterOption) Apply(p *swagger.Parameter) {
+	o(p)
+}
+
+// leafItems returns the innermost items of the array parameter, p, or nil if p is not an array
+func leafItems(p *swagger.Parameter) *swagger.Items {
+	items := p.Items
+	for items != nil && items.Items != nil {
+		items = items.Items
+	}
+	return items
+}
+
+// Items describes the elements of an array parameter; each call nests a further array e.g. Items("array"),
+// Items("integer") for [][]int.  Items should precede the options that apply to the innermost items
+func Items(typ string) ParameterOption {
+	return func(p *swagger.Parameter) {
+		if items := leafItems(p); items != nil {
+			items.Items = &swagger.Items{Type: typ}
+			return
+		}
+		p.Items = &swagger.Items{Type: typ}
+	}
+}
+
+// CollectionFormat determines how the values of an array parameter are delimited: csv, ssv, tsv, pipes, or multi
+func CollectionFormat(v string) ParameterOption {
+	return func(p *swagger.Parameter) {
+		p.CollectionFormat = v
+	}
+}
+
+// Format overrides the format of the parameter; for arrays, the format of the innermost items
+func Format(v string) ParameterOption {
+	return func(p *swagger.Parameter) {
+		if items := leafItems(p); items != nil {
+			items.Format = v
+			return
+		}
+		p.Format = v
+	}
+}
+
+// Enum lists the allowed values of the parameter; for arrays, the allowed values of the innermost items
+func Enum(values ...interface{}) ParameterOption {
+	return func(p *swagger.Parameter) {
+		if items := leafItems(p); items != nil {
+			items.Enum = values
+			return
+		}
+		p.Enum = values
+	}
+}
+
+// Default sets the value the server assumes when the parameter is not provided
+func Default(v interface{}) ParameterOption {
+	return func(p *swagger.Parameter) {
+		p.Default = v
+	}
+}
+
+// constraints returns the constraints of the parameter; for arrays, the constraints of the innermost items
+func constraints(p *swagger.Parameter) *swagger.Constraints {
+	if items := leafItems(p); items != nil {
+		return &items.Constraints
+	}
+	return &p.Constraints
+}
+
+// Minimum sets the inclusive lower bound of a numeric parameter
+func Minimum(v float64) ParameterOption {
+	return func(p *swagger.Parameter) {
+		constraints(p).Minimum = &v
+	}
+}
+
+// Maximum sets the inclusive upper bound of a numeric parameter
+func Maximum(v float64) ParameterOption {
+	return func(p *swagger.Parameter) {
+		constraints(p).Maximum = &v
+	}
+}
+
+// MinLength sets the minimum length of a string parameter
+func MinLength(v int64) ParameterOption {
+	return func(p *swagger.Parameter) {
+		constraints(p).MinLength = &v
+	}
+}
+
+// MaxLength sets the maximum length of a string parameter
+func MaxLength(v int64) ParameterOption {
+	return func(p *swagger.Parameter) {
+		constraints(p).MaxLength = &v
+	}
+}
+
+// Pattern sets the regular expression a string parameter must match
+func Pattern(v string) ParameterOption {
+	return func(p *swagger.Parameter) {
+		constraints(p).Pattern = v
+	}
+}
+
+// MinItems sets the minimum number of values of an array parameter
+func MinItems(v int64) ParameterOption {
+	return func(p *swagger.Parameter) {
+		p.MinItems = &v
+	}
+}
+
+// MaxItems sets the maximum number of values of an array parameter
+func MaxItems(v int64) ParameterOption {
+	return func(p *swagger.Parameter) {
+		p.MaxItems = &v
 	}
 }
 
 // Path defines a path parameter for the endpoint; name, typ, description, and required correspond to the matching
 // swagger fields
-func Path(name, typ, description string, required bool) Option {
+func Path(name, typ, description string, required bool, options ...ParameterOption) Option {
 	p := swagger.Parameter{
 		Name:        name,
 		In:          "path",
@@ -96,12 +267,12 @@ func Path(name, typ, description string, required bool) Option {
 		Description: description,
 		Required:    required,
 	}
-	return parameter(p)
+	return parameter(p, options...)
 }
 
 // Query defines a query parameter for the endpoint; name, typ, description, and required correspond to the matching
 // swagger fields
-func Query(name, typ, description string, required bool) Option {
+func Query(name, typ, description string, required bool, options ...ParameterOption) Option {
 	p := swagger.Parameter{
 		Name:        name,
 		In:          "query",
@@ -109,7 +280,164 @@ func Query(name, typ, description string, required bool) Option {
 		Description: description,
 		Required:    required,
 	}
-	return parameter(p)
+	return parameter(p, options...)
+}
+
+// HeaderParam defines a header parameter for the endpoint e.g. X-Request-ID; name, typ, description, and required
+// correspond to the matching swagger fields.  See Header for response headers
+func HeaderParam(name, typ, description string, required bool, options ...ParameterOption) Option {
+	p := swagger.Parameter{
+		Name:        name,
+		In:          "header",
+		Type:        typ,
+		Description: description,
+		Required:    required,
+	}
+	return parameter(p, options...)
+}
+
+// Cookie defines a cookie parameter for the endpoint; name, typ, description, and required correspond to the matching
+// swagger fields.  Cookie parameters are only supported by OpenAPI 3.0 and are omitted from swagger 2.0 documents
+func Cookie(name, typ, description string, required bool, options ...ParameterOption) Option {
+	p := swagger.Parameter{
+		Name:        name,
+		In:          "cookie",
+		Type:        typ,
+		Description: description,
+		Required:    required,
+	}
+	return parameter(p, options...)
+}
+
+const (
+	formURLEncoded    = "application/x-www-form-urlencoded"
+	multipartFormData = "multipart/form-data"
+)
+
+// consumesForm ensures the endpoint consumes a form; multipart forms are required to upload files.  The default
+// consumes is replaced, whereas the form media type is appended to consumes that were set explicitly
+func consumesForm(b *Builder, multipart bool) {
+	for _, mediaType := range b.Endpoint.Consumes {
+		if mediaType == multipartFormData || (mediaType == formURLEncoded && !multipart) {
+			return
+		}
+	}
+
+	mediaType := formURLEncoded
+	if multipart {
+		mediaType = multipartFormData
+	}
+
+	if b.consumes {
+		consumes := b.Endpoint.Consumes
+		b.Endpoint.Consumes = append(consumes[:len(consumes):len(consumes)], mediaType) // never the caller's array
+	} else {
+		b.Endpoint.Consumes = []string{mediaType}
+	}
+}
+
+// FormData defines a form parameter for the endpoint; name, typ, description, and required correspond to the matching
+// swagger fields.  Unless the endpoint already consumes a form, application/x-www-form-urlencoded is added to consumes
+func FormData(name, typ, description string, required bool, options ...ParameterOption) Option {
+	p := swagger.Parameter{
+		Name:        name,
+		In:          "formData",
+		Type:        typ,
+		Description: description,
+		Required:    required,
+	}
+	return func(b *Builder) {
+		parameter(p, options...)(b)
+		consumesForm(b, false)
+	}
+}
+
+// File defines a file upload parameter for the endpoint; multipart/form-data is added to consumes
+func File(name, description string, required bool, options ...ParameterOption) Option {
+	p := swagger.Parameter{
+		Name:        name,
+		In:          "formData",
+		Type:        "file",
+		Description: description,
+		Required:    required,
+	}
+	return func(b *Builder) {
+		parameter(p, options...)(b)
+		consumesForm(b, true)
+	}
+}
+
+// typedParameter defines the parameter, name, located in in, whose type, format, and items are derived from the go
+// type of prototype
+func typedParameter(in, name string, prototype interface{}, description string, required bool, options []ParameterOption) Option {
+	return func(b *Builder) {
+		p, err := swagger.MakeParameter(in, name, prototype)
+		if err != nil {
+			b.errs = append(b.errs, err)
+			return
+		}
+
+		p.Description = description
+		p.Required = p.Required || required
+		parameter(p, options...)(b)
+	}
+}
+
+// PathType defines a path parameter for the endpoint whose type, format, and items are derived from the go type of
+// prototype e.g. int64(0) or reflect.TypeOf(uuid.UUID{}).  Path parameters are always required
+func PathType(name string, prototype interface{}, description string, options ...ParameterOption) Option {
+	return typedParameter("path", name, prototype, description, true, options)
+}
+
+// QueryType defines a query parameter for the endpoint whose type, format, and items are derived from the go type of
+// prototype e.g. []string{} or reflect.TypeOf(Status(""))
+func QueryType(name string, prototype interface{}, description string, required bool, options ...ParameterOption) Option {
+	return typedParameter("query", name, prototype, description, required, options)
+}
+
+// HeaderType defines a header parameter for the endpoint whose type, format, and items are derived from the go type of
+// prototype
+func HeaderType(name string, prototype interface{}, description string, required bool, options ...ParameterOption) Option {
+	return typedParameter("header", name, prototype, description, required, options)
+}
+
+// CookieType defines a cookie parameter for the endpoint whose type, format, and items are derived from the go type of
+// prototype.  Cookie parameters are only supported by OpenAPI 3.0 and are omitted from swagger 2.0 documents
+func CookieType(name string, prototype interface{}, description string, required bool, options ...ParameterOption) Option {
+	return typedParameter("cookie", name, prototype, description, required, options)
+}
+
+// FormDataType defines a form parameter for the endpoint whose type, format, and items are derived from the go type of
+// prototype.  Unless the endpoint already consumes a form, application/x-www-form-urlencoded is added to consumes
+func FormDataType(name string, prototype interface{}, description string, required bool, options ...ParameterOption) Option {
+	return func(b *Builder) {
+		typedParameter("formData", name, prototype, description, required, options)(b)
+		consumesForm(b, false)
+	}
+}
+
+// ParameterRef references the api parameter, name, rather than repeating it; see swag.Parameter
+func ParameterRef(name string) Option {
+	return parameter(swagger.Parameter{Ref: swagger.MakeParameterRef(name)})
+}
+
+// Parameters defines a parameter for each field of the struct prototype tagged with the location of the parameter e.g.
+// `query:"limit"`.  See swagger.MakeParameters for the struct tags understood
+func Parameters(prototype interface{}) Option {
+	return func(b *Builder) {
+		params, err := swagger.MakeParameters(prototype)
+		if err != nil {
+			b.errs = append(b.errs, err)
+			return
+		}
+
+		for _, p := range params {
+			parameter(p)(b)
+			if p.In == "formData" {
+				consumesForm(b, false)
+			}
+		}
+	}
 }
 
 // Body defines a body parameter for the swagger endpoint as would commonly be used for the POST, PUT, and PATCH methods
@@ -209,13 +537,58 @@ func ResponseType(code int, t reflect.Type, description string, opts ...Response
 	}
 }
 
+// ResponseRef sets the endpoint response for the specified code to the api response, name, rather than repeating it;
+// see swag.Response
+func ResponseRef(code int, name string) Option {
+	return func(b *Builder) {
+		if b.Endpoint.Responses == nil {
+			b.Endpoint.Responses = map[string]swagger.Response{}
+		}
+
+		b.Endpoint.Responses[strconv.Itoa(code)] = swagger.Response{Ref: swagger.MakeResponseRef(name)}
+	}
+}
+
 // Response sets the endpoint response for the specified code; may be used multiple times with different status codes
 func Response(code int, prototype interface{}, description string, opts ...ResponseOption) Option {
 	return ResponseType(code, reflect.TypeOf(prototype), description, opts...)
 }
 
-// New constructs a new swagger endpoint using the fields and functional options provided
+// methods holds the http methods swagger is able to describe
+var methods = map[string]bool{
+	"DELETE":  true,
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"TRACE":   true,
+	"CONNECT": true,
+}
+
+// New constructs a new swagger endpoint using the fields and functional options provided.  New panics if the method
+// is invalid, the path does not begin with /, or any option is invalid; see NewE
 func New(method, path, summary string, options ...Option) *swagger.Endpoint {
+	e, err := NewE(method, path, summary, options...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// NewE is the same as New, but returns the configuration errors accumulated from the method, path, and every option
+// rather than panicking
+func NewE(method, path, summary string, options ...Option) (*swagger.Endpoint, error) {
+	e := newBuilder(method, path, summary, options)
+	if err := e.errs.Err(); err != nil {
+		return nil, err
+	}
+	return e.Endpoint, nil
+}
+
+// newBuilder applies the options to a new endpoint, accumulating the errors of the method, path, and options
+func newBuilder(method, path, summary string, options []Option) *Builder {
 	method = strings.ToUpper(method)
 	e := &Builder{
 		Endpoint: &swagger.Endpoint{
@@ -229,9 +602,16 @@ func New(method, path, summary string, options ...Option) *swagger.Endpoint {
 		},
 	}
 
+	if !methods[method] {
+		e.errs = append(e.errs, fmt.Errorf("invalid method, %v", method))
+	}
+	if !strings.HasPrefix(path, "/") {
+		e.errs = append(e.errs, fmt.Errorf("path, %v, must begin with /", path))
+	}
+
 	for _, opt := range options {
 		opt.Apply(e)
 	}
 
-	return e.Endpoint
+	return e
 }
@@ -15,6 +15,7 @@
 package endpoint_test
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"testing"
@@ -46,6 +47,27 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, []string{}, e.Tags)
 }
 
+func TestNewE(t *testing.T) {
+	e, err := endpoint.NewE("get", "/pets", "list pets")
+	assert.Nil(t, err)
+	assert.Equal(t, "GET", e.Method)
+
+	e, err = endpoint.NewE("fetch", "pets", "list pets")
+	assert.Nil(t, e)
+	if errs, ok := err.(swagger.Errors); assert.True(t, ok, "expected every error to be reported") {
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], "invalid method, FETCH")
+		assert.EqualError(t, errs[1], "path, pets, must begin with /")
+	}
+
+	assert.Panics(t, func() {
+		endpoint.New("fetch", "/pets", "list pets")
+	})
+	assert.Panics(t, func() {
+		endpoint.New("get", "pets", "list pets")
+	}, "expected New to check the path as NewE does")
+}
+
 func TestTags(t *testing.T) {
 	e := endpoint.New("get", "/", "get thing",
 		endpoint.Tags("blah"),
@@ -122,6 +144,250 @@ func TestQuery(t *testing.T) {
 	assert.Equal(t, expected, e.Parameters[0])
 }
 
+func TestHeaderParam(t *testing.T) {
+	expected := swagger.Parameter{
+		In:          "header",
+		Name:        "X-Request-ID",
+		Description: "the description",
+		Required:    true,
+		Type:        "string",
+	}
+
+	e := endpoint.New("get", "/", "get thing",
+		endpoint.HeaderParam(expected.Name, expected.Type, expected.Description, expected.Required),
+	)
+
+	assert.Equal(t, 1, len(e.Parameters))
+	assert.Equal(t, expected, e.Parameters[0])
+}
+
+func TestCookie(t *testing.T) {
+	e := endpoint.New("get", "/", "get thing",
+		endpoint.Cookie("session", "string", "the description", false),
+		endpoint.Query("id", "string", "the description", false),
+	)
+
+	assert.Equal(t, 2, len(e.Parameters))
+	assert.Equal(t, "cookie", e.Parameters[0].In)
+
+	data, err := json.Marshal(e)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "session", "expected cookie parameters to be omitted from swagger 2.0")
+	assert.Contains(t, string(data), `"in":"query"`)
+}
+
+func TestFormData(t *testing.T) {
+	e := endpoint.New("post", "/", "post thing",
+		endpoint.FormData("name", "string", "the description", true),
+	)
+
+	assert.Equal(t, 1, len(e.Parameters))
+	assert.Equal(t, "formData", e.Parameters[0].In)
+	assert.Equal(t, []string{"application/x-www-form-urlencoded"}, e.Consumes)
+
+	e = endpoint.New("post", "/", "post thing",
+		endpoint.File("file", "the upload", true),
+		endpoint.FormData("name", "string", "the description", true),
+	)
+
+	assert.Equal(t, 2, len(e.Parameters))
+	assert.Equal(t, swagger.Parameter{
+		In:          "formData",
+		Name:        "file",
+		Description: "the upload",
+		Required:    true,
+		Type:        "file",
+	}, e.Parameters[0])
+	assert.Equal(t, []string{"multipart/form-data"}, e.Consumes, "expected multipart to be retained")
+
+	e = endpoint.New("post", "/", "post thing",
+		endpoint.Consumes("application/x-www-form-urlencoded", "application/json"),
+		endpoint.File("file", "the upload", true),
+	)
+	assert.Equal(t, []string{"application/x-www-form-urlencoded", "application/json", "multipart/form-data"}, e.Consumes,
+		"expected explicit consumes to be retained")
+
+	consumes := make([]string, 1, 2)
+	consumes[0] = "application/json"
+	endpoint.New("post", "/", "post thing",
+		endpoint.Consumes(consumes...),
+		endpoint.FormData("name", "string", "the description", true),
+	)
+	assert.Equal(t, "", consumes[:2][1], "expected the caller's consumes to be left unmodified")
+}
+
+func TestParameterOptions(t *testing.T) {
+	status := endpoint.Query("status", "array", "the description", false,
+		endpoint.Items("string"),
+		endpoint.CollectionFormat("multi"),
+		endpoint.Enum("available", "pending", "sold"),
+		endpoint.MinItems(1),
+	)
+	e := endpoint.New("get", "/{id}", "get thing",
+		endpoint.Path("id", "string", "the description", true,
+			endpoint.Format("uuid"),
+			endpoint.Pattern("^[0-9a-f-]+$"),
+		),
+		endpoint.Query("limit", "integer", "the description", false,
+			endpoint.Default(20),
+			endpoint.Minimum(1),
+			endpoint.Maximum(100),
+		),
+		status,
+		endpoint.Query("grid", "array", "the description", false,
+			endpoint.Items("array"),
+			endpoint.Items("integer"),
+			endpoint.Format("int32"),
+		),
+	)
+
+	assert.Equal(t, 4, len(e.Parameters))
+
+	id := e.Parameters[0]
+	assert.Equal(t, "uuid", id.Format)
+	assert.Equal(t, "^[0-9a-f-]+$", id.Pattern)
+
+	limit := e.Parameters[1]
+	assert.Equal(t, 20, limit.Default)
+	assert.Equal(t, 1.0, *limit.Minimum)
+	assert.Equal(t, 100.0, *limit.Maximum)
+
+	minItems := int64(1)
+	assert.Equal(t, swagger.Parameter{
+		In:               "query",
+		Name:             "status",
+		Description:      "the description",
+		Type:             "array",
+		Items:            &swagger.Items{Type: "string", Enum: []interface{}{"available", "pending", "sold"}},
+		CollectionFormat: "multi",
+		Constraints:      swagger.Constraints{MinItems: &minItems},
+	}, e.Parameters[2])
+
+	grid := e.Parameters[3]
+	assert.Equal(t, &swagger.Items{Type: "array", Items: &swagger.Items{Type: "integer", Format: "int32"}}, grid.Items)
+
+	other := endpoint.New("get", "/", "get thing", status)
+	assert.False(t, e.Parameters[2].Items == other.Parameters[0].Items, "expected items not to be shared between endpoints")
+}
+
+type Status string
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+func TestTypedParameters(t *testing.T) {
+	e := endpoint.New("get", "/{petId}", "get thing",
+		endpoint.PathType("petId", int64(0), "the description"),
+		endpoint.QueryType("status", []Status{}, "the description", false,
+			endpoint.Enum("available", "sold"),
+			endpoint.CollectionFormat("multi"),
+		),
+		endpoint.HeaderType("X-Rate-Limit", reflect.TypeOf(int32(0)), "the description", true),
+		endpoint.CookieType("session", "", "the description", false),
+	)
+
+	assert.Equal(t, 4, len(e.Parameters))
+	assert.Equal(t, swagger.Parameter{
+		In:          "path",
+		Name:        "petId",
+		Description: "the description",
+		Required:    true,
+		Type:        "integer",
+		Format:      "int64",
+	}, e.Parameters[0])
+	assert.Equal(t, swagger.Parameter{
+		In:               "query",
+		Name:             "status",
+		Description:      "the description",
+		Type:             "array",
+		Items:            &swagger.Items{Type: "string", Enum: []interface{}{"available", "sold"}},
+		CollectionFormat: "multi",
+	}, e.Parameters[1])
+	assert.Equal(t, "int32", e.Parameters[2].Format)
+	assert.True(t, e.Parameters[2].Required)
+	assert.Equal(t, "cookie", e.Parameters[3].In)
+
+	e = endpoint.New("post", "/", "post thing",
+		endpoint.FormDataType("tags", []string{}, "the description", false),
+	)
+	assert.Equal(t, "array", e.Parameters[0].Type)
+	assert.Equal(t, []string{"application/x-www-form-urlencoded"}, e.Consumes)
+
+	_, err := endpoint.NewE("get", "/", "get thing",
+		endpoint.QueryType("model", Model{}, "the description", false),
+	)
+	assert.EqualError(t, err, "query parameter, model, has unsupported type, endpoint_test.Model")
+}
+
+func TestInvalidParameterType(t *testing.T) {
+	_, err := endpoint.NewE("get", "/{petId}", "get thing",
+		endpoint.Path("petId", "int", "the description", true),
+		endpoint.Query("ids", "array", "the description", false, endpoint.Items("Integer")),
+		endpoint.File("image", "the description", false),
+		endpoint.Query("upload", "file", "the description", false),
+		endpoint.Query("tags", "array", "the description", false),
+		endpoint.Query("grid", "array", "the description", false, endpoint.Items("array")),
+	)
+	if errs, ok := err.(swagger.Errors); assert.True(t, ok, "expected every error to be reported") {
+		assert.Len(t, errs, 5)
+		assert.EqualError(t, errs[0], "path parameter, petId, has invalid type, int")
+		assert.EqualError(t, errs[1], "query parameter, ids, has invalid items type, Integer")
+		assert.EqualError(t, errs[2], "query parameter, upload, has invalid type, file")
+		assert.EqualError(t, errs[3], "query parameter, tags, is an array with no items; see Items")
+		assert.EqualError(t, errs[4], "query parameter, grid, has array items with no items")
+	}
+
+	assert.Panics(t, func() {
+		endpoint.New("get", "/", "get thing", endpoint.Query("id", "Integer", "the description", true))
+	})
+}
+
+func TestParameters(t *testing.T) {
+	type Params struct {
+		ID    string `path:"id" description:"the description"`
+		Limit int    `query:"limit" validate:"min=1,max=100"`
+		Name  string `form:"name"`
+	}
+
+	e := endpoint.New("post", "/{id}", "post thing",
+		endpoint.Parameters(Params{}),
+	)
+
+	assert.Equal(t, 3, len(e.Parameters))
+	assert.Equal(t, swagger.Parameter{
+		In:          "path",
+		Name:        "id",
+		Description: "the description",
+		Required:    true,
+		Type:        "string",
+	}, e.Parameters[0])
+	assert.Equal(t, "query", e.Parameters[1].In)
+	assert.Equal(t, 100.0, *e.Parameters[1].Maximum)
+	assert.Equal(t, []string{"application/x-www-form-urlencoded"}, e.Consumes)
+
+	_, err := endpoint.NewE("get", "/", "get thing",
+		endpoint.Parameters(struct {
+			Model Model `query:"model"`
+		}{}),
+	)
+	assert.EqualError(t, err, "query parameter, model, has unsupported type, endpoint_test.Model")
+}
+
+func TestParameterRef(t *testing.T) {
+	e := endpoint.New("get", "/", "get thing",
+		endpoint.ParameterRef("limit"),
+		endpoint.ResponseRef(http.StatusNotFound, "NotFound"),
+	)
+
+	assert.Equal(t, []swagger.Parameter{{Ref: "#/parameters/limit"}}, e.Parameters)
+	assert.Equal(t, swagger.Response{Ref: "#/responses/NotFound"}, e.Responses["404"])
+
+	data, err := json.Marshal(e.Parameters[0])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"$ref": "#/parameters/limit"}`, string(data))
+}
+
 type Model struct {
 	String string `json:"s"`
 }
@@ -21,31 +21,69 @@ import (
 	"net/http"
 	"path"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 )
 
 // Object represents the object entity from the swagger definition
 type Object struct {
-	IsArray    bool                `json:"-"`
-	GoType     reflect.Type        `json:"-"`
-	Name       string              `json:"-"`
-	Type       string              `json:"type"`
-	Format     string              `json:"format,omitempty"`
-	Required   []string            `json:"required,omitempty"`
-	Properties map[string]Property `json:"properties,omitempty"`
+	IsArray       bool                `json:"-"`
+	GoType        reflect.Type        `json:"-"`
+	Name          string              `json:"-"`
+	Ref           string              `json:"$ref,omitempty"`
+	Type          string              `json:"type,omitempty"`
+	Format        string              `json:"format,omitempty"`
+	Description   string              `json:"description,omitempty"`
+	Discriminator string              `json:"discriminator,omitempty"`
+	Required      []string            `json:"required,omitempty"`
+	Properties    map[string]Property `json:"properties,omitempty"`
+	AllOf         []Object            `json:"allOf,omitempty"`
+
+	AdditionalProperties *Property `json:"additionalProperties,omitempty"`
+
+	// Items, Enum, and Example describe definitions that are not objects e.g. those of a parsed document
+	Items   *Items        `json:"items,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty"`
+	Example interface{}   `json:"example,omitempty"`
+
+	// DiscriminatorValue is the value of the discriminator property that identifies the definition.  Swagger 2.0
+	// expects the value to be the definition name, so tooling that supports the extension relies on it instead
+	DiscriminatorValue string `json:"x-discriminator-value,omitempty"`
+
+	// DiscriminatorMapping maps discriminator values onto definition names.  Swagger 2.0 has no equivalent and
+	// expects discriminator values to be the definition names; the mapping is included in the OpenAPI 3.0 document
+	DiscriminatorMapping map[string]string `json:"-"`
 }
 
 // Property represents the property entity from the swagger definition
 type Property struct {
-	GoType      reflect.Type `json:"-"`
-	Type        string       `json:"type,omitempty"`
-	Description string       `json:"description,omitempty"`
-	Enum        []string     `json:"enum,omitempty"`
-	Format      string       `json:"format,omitempty"`
-	Ref         string       `json:"$ref,omitempty"`
-	Example     string       `json:"example,omitempty"`
-	Items       *Items       `json:"items,omitempty"`
+	GoType      reflect.Type  `json:"-"`
+	Type        string        `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Ref         string        `json:"$ref,omitempty"`
+	Example     interface{}   `json:"example,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Items       *Items        `json:"items,omitempty"`
+	ReadOnly    bool          `json:"readOnly,omitempty"`
+	Nullable    bool          `json:"x-nullable,omitempty"`
+	Constraints
+
+	AdditionalProperties *Property `json:"additionalProperties,omitempty"`
+
+	// Required and Properties describe anonymous structs, which have no definition of their own
+	Required   []string            `json:"required,omitempty"`
+	Properties map[string]Property `json:"properties,omitempty"`
+
+	// AllOf holds the reference of properties that are also nullable, read only, or write only; the spec ignores the
+	// siblings of $ref
+	AllOf []Property `json:"allOf,omitempty"`
+
+	// WriteOnly properties are only sent in requests.  Swagger 2.0 has no equivalent; the marker is included in the
+	// OpenAPI 3.0 document
+	WriteOnly bool `json:"-"`
 }
 
 // Contact represents the contact entity from the swagger definition; used by Info
@@ -79,6 +117,13 @@ type SecurityScheme struct {
 	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
 	TokenURL         string            `json:"tokenUrl,omitempty"`
 	Scopes           map[string]string `json:"scopes,omitempty"`
+
+	err error // the error recorded by an invalid option
+}
+
+// Err returns the error recorded by an invalid option, if any, e.g. APIKeySecurity with an unknown location
+func (s SecurityScheme) Err() error {
+	return s.err
 }
 
 // SecuritySchemeOption provides additional customizations to the SecurityScheme.
@@ -100,17 +145,31 @@ func BasicSecurity() SecuritySchemeOption {
 
 // APIKeySecurity defines a security scheme for API key authentication. "in" is
 // the location of the API key (query or header). "name" is the name of the
-// header or query parameter to be used.
+// header or query parameter to be used.  If "in" is invalid, the error is
+// recorded on the scheme and reported by swag.New, swag.NewE, and
+// API.Validate; see also APIKeySecurityE
 func APIKeySecurity(name, in string) SecuritySchemeOption {
+	option, err := APIKeySecurityE(name, in)
+	if err != nil {
+		return func(securityScheme *SecurityScheme) {
+			securityScheme.err = err
+		}
+	}
+	return option
+}
+
+// APIKeySecurityE is the same as APIKeySecurity, but returns the error
+// immediately, rather than recording it on the scheme, if "in" is invalid
+func APIKeySecurityE(name, in string) (SecuritySchemeOption, error) {
 	if in != "header" && in != "query" {
-		panic(fmt.Errorf(`APIKeySecurity "in" parameter must be one of: "header" or "query"`))
+		return nil, fmt.Errorf(`APIKeySecurity "in" parameter must be one of: "header" or "query"`)
 	}
 
 	return func(securityScheme *SecurityScheme) {
 		securityScheme.Type = "apiKey"
 		securityScheme.Name = name
 		securityScheme.In = in
-	}
+	}, nil
 }
 
 // OAuth2Scope adds a new scope to the security scheme.
@@ -148,6 +207,33 @@ type Endpoints struct {
 	Patch   *Endpoint `json:"patch,omitempty"`
 	Trace   *Endpoint `json:"trace,omitempty"`
 	Connect *Endpoint `json:"connect,omitempty"`
+
+	// Parameters are shared by every endpoint on the path; an endpoint may override them by name and location
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// MarshalJSON omits cookie parameters, which swagger 2.0 is unable to describe
+func (e *Endpoints) MarshalJSON() ([]byte, error) {
+	type document Endpoints // prevents recursion into MarshalJSON
+	v := document(*e)
+
+	v.Parameters = nil
+	for _, p := range e.Parameters {
+		if p.In != "cookie" {
+			v.Parameters = append(v.Parameters, p)
+		}
+	}
+
+	return json.Marshal(v)
+}
+
+// empty returns true if no endpoints remain on the path
+func (e *Endpoints) empty() bool {
+	empty := true
+	e.Walk(func(*Endpoint) {
+		empty = false
+	})
+	return empty
 }
 
 // ServeHTTP allows endpoints to serve itself using the builtin http mux
@@ -232,10 +318,22 @@ type API struct {
 	Schemes             []string                  `json:"schemes,omitempty"`
 	Paths               map[string]*Endpoints     `json:"paths,omitempty"`
 	Definitions         map[string]Object         `json:"definitions,omitempty"`
+	Parameters          map[string]Parameter      `json:"parameters,omitempty"`
+	Responses           map[string]Response       `json:"responses,omitempty"`
 	Tags                []Tag                     `json:"tags"`
 	Host                string                    `json:"host"`
 	SecurityDefinitions map[string]SecurityScheme `json:"securityDefinitions,omitempty"`
 	Security            *SecurityRequirement      `json:"security,omitempty"`
+
+	// SchemaOptions customizes how the go types of endpoints are converted into definitions
+	SchemaOptions SchemaOptions `json:"-"`
+
+	reflector *reflector
+
+	// mux guards Paths, Definitions, Parameters, and Responses, which are replaced rather than modified, so that
+	// endpoints may be added and removed while the api is being served
+	mux      sync.RWMutex
+	revision uint64
 }
 
 func (a *API) clone() *API {
@@ -246,22 +344,30 @@ func (a *API) clone() *API {
 		Schemes:             a.Schemes,
 		Paths:               a.Paths,
 		Definitions:         a.Definitions,
+		Parameters:          a.Parameters,
+		Responses:           a.Responses,
 		Tags:                a.Tags,
 		Host:                a.Host,
 		SecurityDefinitions: a.SecurityDefinitions,
 		Security:            a.Security,
+		SchemaOptions:       a.SchemaOptions,
+		reflector:           a.reflector,
 	}
 }
 
-func (a *API) addPath(e *Endpoint) {
-	if a.Paths == nil {
-		a.Paths = map[string]*Endpoints{}
-	}
+// snapshot returns a consistent copy of the api along with its revision; the copy shares maps with the api, which is
+// safe as they are never modified once published
+func (a *API) snapshot() (*API, uint64) {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
 
-	v, ok := a.Paths[e.Path]
-	if !ok {
-		v = &Endpoints{}
-		a.Paths[e.Path] = v
+	return a.clone(), a.revision
+}
+
+func (a *API) addPath(e *Endpoint) error {
+	v := &Endpoints{}
+	if existing, ok := a.Paths[e.Path]; ok {
+		*v = *existing
 	}
 
 	switch strings.ToUpper(e.Method) {
@@ -284,98 +390,313 @@ func (a *API) addPath(e *Endpoint) {
 	case "CONNECT":
 		v.Connect = e
 	default:
-		panic(fmt.Errorf("invalid method, %v", e.Method))
+		return fmt.Errorf("invalid method, %v", e.Method)
+	}
+
+	paths := make(map[string]*Endpoints, len(a.Paths)+1)
+	for k, endpoints := range a.Paths {
+		paths[k] = endpoints
 	}
+	paths[e.Path] = v
+	a.Paths = paths
+
+	return nil
 }
 
-func (a *API) addDefinition(e *Endpoint) {
-	if a.Definitions == nil {
-		a.Definitions = map[string]Object{}
+func (a *API) mergeDefinitions(def map[string]Object) {
+	for k, v := range def {
+		if _, ok := a.Definitions[k]; !ok {
+			a.Definitions[k] = v
+		}
 	}
+}
+
+// copyDefinitions replaces the definitions with a copy that may be modified and returns the reflector of the api
+func (a *API) copyDefinitions() *reflector {
+	definitions := make(map[string]Object, len(a.Definitions))
+	for k, v := range a.Definitions {
+		definitions[k] = v
+	}
+	a.Definitions = definitions
+
+	// definition names are assigned per api so that collisions between endpoints can be resolved
+	if a.reflector == nil {
+		a.reflector = &reflector{SchemaOptions: a.SchemaOptions}
+	}
+
+	// definitions the reflector did not create e.g. those parsed from a document, keep their names
+	for name := range a.Definitions {
+		a.reflector.reserve(name)
+	}
+
+	return a.reflector
+}
+
+func (a *API) addDefinition(e *Endpoint) {
+	r := a.copyDefinitions()
 
 	if e.Parameters != nil {
-		for _, p := range e.Parameters {
-			if p.Schema != nil {
-				def := define(p.Schema.Prototype)
-				for k, v := range def {
-					if _, ok := a.Definitions[k]; !ok {
-						a.Definitions[k] = v
-					}
-				}
+		for i, p := range e.Parameters {
+			if p.Schema != nil && p.Schema.Prototype != nil {
+				e.Parameters[i].Schema = r.makeSchema(p.Schema.Prototype)
+				a.mergeDefinitions(r.defineSchema(p.Schema.Prototype))
 			}
 		}
 	}
 
 	if e.Responses != nil {
-		for _, response := range e.Responses {
-			if response.Schema != nil {
-				def := define(response.Schema.Prototype)
-				for k, v := range def {
-					if _, ok := a.Definitions[k]; !ok {
-						a.Definitions[k] = v
-					}
-				}
+		codes := make([]string, 0, len(e.Responses))
+		for code := range e.Responses {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+
+		for _, code := range codes {
+			response := e.Responses[code]
+			if response.Schema != nil && response.Schema.Prototype != nil {
+				response.Schema = r.makeSchema(response.Schema.Prototype)
+				e.Responses[code] = response
+				a.mergeDefinitions(r.defineSchema(response.Schema.Prototype))
 			}
 		}
 	}
 }
 
-// AddEndpoint adds the specified endpoint to the API definition; to generate an endpoint use ```endpoint.New```
+// consumesForm ensures e consumes a form if it references an api form parameter, as endpoint.FormData and
+// endpoint.File do for the parameters they declare.  The default consumes, application/json, is replaced, whereas the
+// form media type is appended to any other consumes
+func (a *API) consumesForm(e *Endpoint) {
+	var form, multipart bool
+	for _, p := range e.Parameters {
+		if p.Ref == "" {
+			continue
+		}
+		if global, ok := a.Parameters[strings.TrimPrefix(p.Ref, parametersPrefix)]; ok && global.In == "formData" {
+			form = true
+			multipart = multipart || global.Type == "file"
+		}
+	}
+	if !form {
+		return
+	}
+
+	for _, mediaType := range e.Consumes {
+		if mediaType == "multipart/form-data" || (mediaType == "application/x-www-form-urlencoded" && !multipart) {
+			return
+		}
+	}
+
+	mediaType := "application/x-www-form-urlencoded"
+	if multipart {
+		mediaType = "multipart/form-data"
+	}
+
+	if len(e.Consumes) == 1 && e.Consumes[0] == "application/json" {
+		e.Consumes = []string{mediaType}
+	} else {
+		e.Consumes = append(e.Consumes[:len(e.Consumes):len(e.Consumes)], mediaType)
+	}
+}
+
+// AddParameter adds a parameter, named name, that endpoints may reference rather than repeat; see
+// endpoint.ParameterRef.  Parameters may be added while the api is being served
+func (a *API) AddParameter(name string, p Parameter) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if p.Schema != nil && p.Schema.Prototype != nil {
+		r := a.copyDefinitions()
+		p.Schema = r.makeSchema(p.Schema.Prototype)
+		a.mergeDefinitions(r.defineSchema(p.Schema.Prototype))
+	}
+
+	parameters := make(map[string]Parameter, len(a.Parameters)+1)
+	for k, v := range a.Parameters {
+		parameters[k] = v
+	}
+	parameters[name] = p
+	a.Parameters = parameters
+	a.revision++
+}
+
+// AddResponse adds a response, named name, that endpoints may reference rather than repeat; see endpoint.ResponseRef.
+// Responses may be added while the api is being served
+func (a *API) AddResponse(name string, response Response) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if response.Schema != nil && response.Schema.Prototype != nil {
+		r := a.copyDefinitions()
+		response.Schema = r.makeSchema(response.Schema.Prototype)
+		a.mergeDefinitions(r.defineSchema(response.Schema.Prototype))
+	}
+
+	responses := make(map[string]Response, len(a.Responses)+1)
+	for k, v := range a.Responses {
+		responses[k] = v
+	}
+	responses[name] = response
+	a.Responses = responses
+	a.revision++
+}
+
+// AddDefinitions defines the go types of the prototypes, and the types they reference, whether or not they are used by
+// any endpoint e.g. for types only referenced by hand-written schemas.  Definitions may be added while the api is
+// being served
+func (a *API) AddDefinitions(prototypes ...interface{}) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	r := a.copyDefinitions()
+	for _, prototype := range prototypes {
+		a.mergeDefinitions(r.define(prototype))
+	}
+	a.revision++
+}
+
+// AddEndpoint adds the specified endpoint to the API definition; to generate an endpoint use ```endpoint.New```.
+// AddEndpoint panics if the endpoint is invalid; see AddEndpointE
 func (a *API) AddEndpoint(e *Endpoint) {
-	a.addPath(e)
+	if err := a.AddEndpointE(e); err != nil {
+		panic(err)
+	}
+}
+
+// AddEndpointE adds the specified endpoint to the API definition or returns an error, leaving the API unmodified, if
+// the endpoint is invalid e.g. has an unknown method.  The api holds a copy of the endpoint, so e may be added to
+// several apis.  Endpoints may be added while the api is being served
+func (a *API) AddEndpointE(e *Endpoint) error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	e = e.clone() // schemas are rewritten using the definition names of this api
+	a.consumesForm(e)
+	if err := a.addPath(e); err != nil {
+		return err
+	}
 	a.addDefinition(e)
+	a.revision++
+
+	return nil
 }
 
-// Handler is a factory method that generates an http.HandlerFunc; if enableCors is true, then the handler will generate
-// cors headers
-func (a *API) Handler(enableCors bool) http.HandlerFunc {
-	mux := &sync.Mutex{}
-	byHostAndScheme := map[string]*API{}
+// RemoveEndpoint removes the endpoint with the specified method and path, returning false if there was no such
+// endpoint.  Definitions are retained, along with their names, even if no remaining endpoint references them; they
+// may be shared with other endpoints, api parameters and responses, or have been added explicitly.  Endpoints may be
+// removed while the api is being served
+func (a *API) RemoveEndpoint(method, path string) bool {
+	a.mux.Lock()
+	defer a.mux.Unlock()
 
-	return func(w http.ResponseWriter, req *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
+	existing, ok := a.Paths[path]
+	if !ok {
+		return false
+	}
 
-		if enableCors {
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, api_key, Authorization")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, PUT")
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-		}
+	v := &Endpoints{}
+	*v = *existing
 
-		w.WriteHeader(http.StatusOK)
+	var removed *Endpoint
+	switch strings.ToUpper(method) {
+	case "DELETE":
+		removed, v.Delete = v.Delete, nil
+	case "GET":
+		removed, v.Get = v.Get, nil
+	case "HEAD":
+		removed, v.Head = v.Head, nil
+	case "OPTIONS":
+		removed, v.Options = v.Options, nil
+	case "POST":
+		removed, v.Post = v.Post, nil
+	case "PUT":
+		removed, v.Put = v.Put, nil
+	case "PATCH":
+		removed, v.Patch = v.Patch, nil
+	case "TRACE":
+		removed, v.Trace = v.Trace, nil
+	case "CONNECT":
+		removed, v.Connect = v.Connect, nil
+	}
+	if removed == nil {
+		return false
+	}
 
-		// customize the swagger header based on host
-		//
-		scheme := ""
-		if req.TLS != nil {
-			scheme = "https"
-		}
-		if v := req.Header.Get("X-Forwarded-Proto"); v != "" {
-			scheme = v
-		}
-		if scheme == "" {
-			scheme = req.URL.Scheme
+	paths := make(map[string]*Endpoints, len(a.Paths))
+	for k, endpoints := range a.Paths {
+		paths[k] = endpoints
+	}
+	if v.empty() {
+		delete(paths, path)
+	} else {
+		paths[path] = v
+	}
+	a.Paths = paths
+	a.revision++
+
+	return true
+}
+
+// MarshalJSON encodes a consistent snapshot of the api
+func (a *API) MarshalJSON() ([]byte, error) {
+	type document API // prevents recursion into MarshalJSON
+	api, _ := a.snapshot()
+
+	// as with endpoints, cookie parameters are omitted along with the references to them
+	parameters := map[string]Parameter{}
+	cookies := map[string]bool{}
+	for name, p := range api.Parameters {
+		if p.In == "cookie" {
+			cookies[MakeParameterRef(name)] = true
+		} else {
+			parameters[name] = p
 		}
-		if scheme == "" {
-			scheme = "http"
+	}
+	if len(cookies) > 0 {
+		api.Parameters = parameters
+		api.Paths = omitParameters(api.Paths, cookies)
+	}
+
+	return json.Marshal((*document)(api))
+}
+
+// omitParameters returns a copy of paths whose endpoints no longer reference the api parameters, refs
+func omitParameters(paths map[string]*Endpoints, refs map[string]bool) map[string]*Endpoints {
+	omitted := make(map[string]*Endpoints, len(paths))
+	for path, endpoints := range paths {
+		v := &Endpoints{}
+		*v = *endpoints
+
+		v.Parameters = nil
+		for _, p := range endpoints.Parameters {
+			if !refs[p.Ref] {
+				v.Parameters = append(v.Parameters, p)
+			}
 		}
 
-		hostAndScheme := req.Host + ":" + scheme
-		mux.Lock()
-		v, ok := byHostAndScheme[hostAndScheme]
-		if !ok {
-			v = a.clone()
-			v.Host = req.Host
-			v.Schemes = []string{scheme}
-			byHostAndScheme[hostAndScheme] = v
+		methods := []**Endpoint{&v.Delete, &v.Head, &v.Get, &v.Options, &v.Post, &v.Put, &v.Patch, &v.Trace, &v.Connect}
+		for _, e := range methods {
+			if *e == nil {
+				continue
+			}
+
+			copied := **e
+			copied.Parameters = nil
+			for _, p := range (*e).Parameters {
+				if !refs[p.Ref] {
+					copied.Parameters = append(copied.Parameters, p)
+				}
+			}
+			*e = &copied
 		}
-		mux.Unlock()
 
-		json.NewEncoder(w).Encode(v)
+		omitted[path] = v
 	}
+	return omitted
 }
 
 // Walk invoke the callback for each endpoints defined in the swagger doc
 func (a *API) Walk(callback func(path string, endpoints *Endpoint)) {
+	a, _ = a.snapshot()
 	for rawPath, endpoints := range a.Paths {
 		u := path.Join(a.BasePath, rawPath)
 		endpoints.Walk(func(endpoint *Endpoint) {
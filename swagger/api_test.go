@@ -15,10 +15,14 @@
 package swagger_test
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"path/filepath"
@@ -106,9 +110,42 @@ func TestAPIKeySecurity(t *testing.T) {
 	assert.Equal(t, scheme.Name, name)
 	assert.Equal(t, scheme.In, in)
 
+	scheme = &swagger.SecurityScheme{}
+	swagger.APIKeySecurity(name, "invalid")(scheme)
+	assert.NotNil(t, scheme.Err(), "expected APIKeySecurity to reject an invalid \"in\" parameter")
+}
+
+func TestAPIKeySecurityE(t *testing.T) {
+	option, err := swagger.APIKeySecurityE("api_key", "query")
+	assert.Nil(t, err)
+	scheme := &swagger.SecurityScheme{}
+	option(scheme)
+	assert.Equal(t, "query", scheme.In)
+
+	option, err = swagger.APIKeySecurityE("api_key", "invalid")
+	assert.NotNil(t, err)
+	assert.Nil(t, option)
+}
+
+func TestAddEndpointE(t *testing.T) {
+	api := &swagger.API{}
+	err := api.AddEndpointE(&swagger.Endpoint{Method: "FETCH", Path: "/"})
+	assert.EqualError(t, err, "invalid method, FETCH")
+	assert.Empty(t, api.Paths, "expected api to be left unmodified")
+
 	assert.Panics(t, func() {
-		swagger.APIKeySecurity(name, "invalid")
-	}, "expected APIKeySecurity to panic with invalid \"in\" parameter")
+		api.AddEndpoint(&swagger.Endpoint{Method: "FETCH", Path: "/"})
+	})
+
+	assert.Nil(t, api.AddEndpointE(&swagger.Endpoint{Method: "get", Path: "/"}))
+	assert.NotNil(t, api.Paths["/"].Get)
+}
+
+func TestErrors(t *testing.T) {
+	a, b := errors.New("a"), errors.New("b")
+	assert.Nil(t, swagger.Errors(nil).Err())
+	assert.Equal(t, a, swagger.Errors{a}.Err())
+	assert.EqualError(t, swagger.Errors{a, b}.Err(), "a; b")
 }
 
 func TestOAuth2Security(t *testing.T) {
@@ -138,3 +175,80 @@ func TestOAuth2Scope(t *testing.T) {
 	assert.Equal(t, "read data", scheme.Scopes["read"])
 	assert.Equal(t, "write data", scheme.Scopes["write"])
 }
+
+func TestRemoveEndpoint(t *testing.T) {
+	api := &swagger.API{}
+	api.AddEndpoint(&swagger.Endpoint{Method: "GET", Path: "/pets"})
+	api.AddEndpoint(&swagger.Endpoint{Method: "POST", Path: "/pets"})
+	paths := api.Paths
+
+	assert.False(t, api.RemoveEndpoint("delete", "/pets"))
+	assert.False(t, api.RemoveEndpoint("get", "/owners"))
+
+	assert.True(t, api.RemoveEndpoint("get", "/pets"))
+	assert.Nil(t, api.Paths["/pets"].Get)
+	assert.NotNil(t, api.Paths["/pets"].Post)
+	assert.NotNil(t, paths["/pets"].Get, "expected previously published paths to be left unmodified")
+
+	assert.True(t, api.RemoveEndpoint("post", "/pets"))
+	assert.NotContains(t, api.Paths, "/pets", "expected empty paths to be removed")
+
+	api.AddEndpoint(&swagger.Endpoint{
+		Method:    "GET",
+		Path:      "/animals",
+		Responses: map[string]swagger.Response{"200": {Schema: &swagger.Schema{Prototype: Animal{}}}},
+	})
+	assert.True(t, api.RemoveEndpoint("get", "/animals"))
+	assert.Contains(t, api.Definitions, "swagger_testAnimal", "expected definitions to be retained")
+}
+
+func TestHandlerRevision(t *testing.T) {
+	api := &swagger.API{Swagger: "2.0"}
+	api.AddEndpoint(&swagger.Endpoint{Method: "GET", Path: "/pets"})
+	h := api.Handler(false)
+
+	paths := func() map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/swagger", nil)
+		w := httptest.NewRecorder()
+		h(w, req)
+
+		doc := map[string]interface{}{}
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&doc))
+		return doc["paths"].(map[string]interface{})
+	}
+
+	assert.Len(t, paths(), 1)
+
+	api.AddEndpoint(&swagger.Endpoint{Method: "GET", Path: "/owners"})
+	assert.Len(t, paths(), 2, "expected cached documents to be invalidated")
+
+	api.RemoveEndpoint("get", "/pets")
+	assert.Len(t, paths(), 1)
+	assert.Contains(t, paths(), "/owners")
+}
+
+func TestConcurrentAddEndpoint(t *testing.T) {
+	api := &swagger.API{}
+	h := api.Handler(false)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			path := "/pets/" + strconv.Itoa(i)
+			api.AddEndpoint(&swagger.Endpoint{Method: "GET", Path: path})
+			api.RemoveEndpoint("get", path)
+			api.AddEndpoint(&swagger.Endpoint{Method: "POST", Path: path})
+		}(i)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost/swagger", nil)
+			h(httptest.NewRecorder(), req)
+			api.Validate()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, api.Paths, 10)
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Comments holds the doc comments of go types and their fields harvested from source.  Types are matched by import
+// path and type name.  The import path of each directory is determined by the enclosing go.mod or GOPATH; the types
+// of directories to which neither applies are matched by package and type name, or by type name alone, provided the
+// match is unique
+type Comments struct {
+	types    map[string]string         // doc comment by import path, or directory, and type name
+	fields   map[string]string         // doc comment by import path, or directory, type, and field name
+	packages map[string][]commentedPkg // packages that declare a type by type name
+}
+
+// commentedPkg identifies a package harvested by ParseComments
+type commentedPkg struct {
+	prefix string // import path, or directory if the import path is unknown
+	name   string // package name
+	known  bool   // true if prefix is the import path
+}
+
+// ParseComments harvests the doc comments of the types, and their fields, declared by the go packages in dirs.  Test
+// files are ignored
+func ParseComments(dirs ...string) (*Comments, error) {
+	c := &Comments{
+		types:    map[string]string{},
+		fields:   map[string]string{},
+		packages: map[string][]commentedPkg{},
+	}
+
+	for _, dir := range dirs {
+		if err := c.parse(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// modulePath returns the module path declared by the go.mod file, data
+func modulePath(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// importPath returns the import path of the package in dir, as determined by the enclosing go.mod or GOPATH, or false
+// if it cannot be determined
+func importPath(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for d := abs; ; {
+		if data, err := ioutil.ReadFile(filepath.Join(d, "go.mod")); err == nil {
+			module := modulePath(data)
+			rel, err := filepath.Rel(d, abs)
+			if module == "" || err != nil {
+				return "", false
+			}
+			return path.Join(module, filepath.ToSlash(rel)), true
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		src := filepath.Join(gopath, "src") + string(filepath.Separator)
+		if strings.HasPrefix(abs, src) {
+			return filepath.ToSlash(abs[len(src):]), true
+		}
+	}
+
+	return "", false
+}
+
+func isSource(info os.FileInfo) bool {
+	return !strings.HasSuffix(info.Name(), "_test.go")
+}
+
+func (c *Comments) parse(dir string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, isSource, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	prefix, known := importPath(dir)
+	if !known {
+		if prefix, err = filepath.Abs(dir); err != nil {
+			return err
+		}
+	}
+
+	for _, pkg := range pkgs {
+		for _, typ := range doc.New(pkg, dir, doc.AllDecls).Types {
+			key := prefix + "." + typ.Name
+			c.types[key] = strings.TrimSpace(typ.Doc)
+			c.packages[typ.Name] = append(c.packages[typ.Name], commentedPkg{prefix: prefix, name: pkg.Name, known: known})
+
+			for _, spec := range typ.Decl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != typ.Name {
+					continue
+				}
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					c.parseFields(key, st)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Comments) parseFields(key string, st *ast.StructType) {
+	for _, f := range st.Fields.List {
+		text := f.Doc.Text()
+		if text == "" {
+			text = f.Comment.Text()
+		}
+		if text == "" {
+			continue
+		}
+
+		for _, name := range f.Names {
+			c.fields[key+"."+name.Name] = strings.TrimSpace(text)
+		}
+	}
+}
+
+// key returns the key of the go type, t, or false if no comments were harvested for t
+func (c *Comments) key(t reflect.Type) (string, bool) {
+	if c == nil || t.Name() == "" {
+		return "", false
+	}
+
+	key := t.PkgPath() + "." + t.Name()
+	if _, ok := c.types[key]; ok {
+		return key, true
+	}
+
+	// packages of known import path have already been matched exactly above
+	var packages, named []commentedPkg
+	for _, pkg := range c.packages[t.Name()] {
+		if pkg.known {
+			continue
+		}
+		packages = append(packages, pkg)
+		if pkg.name == path.Base(t.PkgPath()) {
+			named = append(named, pkg)
+		}
+	}
+	if len(named) == 1 {
+		return named[0].prefix + "." + t.Name(), true
+	}
+
+	if len(packages) == 1 {
+		return packages[0].prefix + "." + t.Name(), true
+	}
+
+	return "", false
+}
+
+// typeDoc returns the doc comment of the go type, t
+func (c *Comments) typeDoc(t reflect.Type) string {
+	key, ok := c.key(t)
+	if !ok {
+		return ""
+	}
+	return c.types[key]
+}
+
+// fieldDoc returns the doc comment of the struct field, f, of the go type, t.  Promoted fields are documented by the
+// embedded struct that declares them
+func (c *Comments) fieldDoc(t reflect.Type, f field) string {
+	owner := t
+	for _, i := range f.index[:len(f.index)-1] {
+		owner = indirect(owner.Field(i).Type)
+	}
+
+	key, ok := c.key(owner)
+	if !ok {
+		return ""
+	}
+	return c.fields[key+"."+f.field.Name]
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	commented "github.com/savaki/swag/swagger/testdata/comments"
+	a "github.com/savaki/swag/swagger/testdata/models/a"
+	b "github.com/savaki/swag/swagger/testdata/models/b"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComments(t *testing.T) {
+	comments, err := ParseComments("testdata/comments")
+	assert.Nil(t, err)
+
+	r := &reflector{SchemaOptions: SchemaOptions{Comments: comments}}
+	obj := r.defineObject(commented.Commented{})
+	assert.Equal(t, "Commented is a documented model", obj.Description)
+	assert.Equal(t, "ID uniquely identifies the model", obj.Properties["id"].Description)
+	assert.Equal(t, "Name is trailing", obj.Properties["name"].Description)
+	assert.Equal(t, "from the tag", obj.Properties["tagged"].Description, "expected tags to take precedence")
+	assert.Equal(t, "A and B share a comment", obj.Properties["A"].Description)
+	assert.Equal(t, "A and B share a comment", obj.Properties["B"].Description)
+	assert.Equal(t, "", obj.Properties["Undocumented"].Description)
+	assert.Equal(t, "Created is promoted", obj.Properties["created"].Description)
+
+	obj = defineObject(commented.Commented{})
+	assert.Equal(t, "", obj.Description, "expected comments to be opt-in")
+	assert.Equal(t, "", obj.Properties["id"].Description)
+}
+
+func TestCommentsInvalidDir(t *testing.T) {
+	_, err := ParseComments("testdata/missing")
+	assert.NotNil(t, err)
+}
+
+func TestCommentsSamePackageName(t *testing.T) {
+	comments, err := ParseComments("testdata/models/a", "testdata/models/b")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "User is declared by package a", comments.typeDoc(reflect.TypeOf(a.User{})))
+	assert.Equal(t, "User is declared by package b", comments.typeDoc(reflect.TypeOf(b.User{})))
+
+	comments, err = ParseComments("testdata/models/a")
+	assert.Nil(t, err)
+	assert.Equal(t, "", comments.typeDoc(reflect.TypeOf(b.User{})),
+		"expected packages of known import path to document only their own types")
+	assert.Equal(t, "", comments.typeDoc(userA()))
+}
+
+func TestCommentsUnknownImportPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "comments")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	data, err := ioutil.ReadFile("testdata/models/a/models.go")
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "models.go"), data, 0644))
+
+	if _, ok := importPath(dir); ok {
+		t.Skip("temp dir has a known import path")
+	}
+
+	comments, err := ParseComments(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "User is declared by package a", comments.typeDoc(reflect.TypeOf(b.User{})),
+		"expected the only candidate to be matched by type name")
+}
+
+func TestImportPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "comments")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "internal", "models"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0644))
+
+	v, ok := importPath(filepath.Join(dir, "internal", "models"))
+	assert.True(t, ok)
+	assert.Equal(t, "example.com/app/internal/models", v)
+
+	v, ok = importPath(dir)
+	assert.True(t, ok)
+	assert.Equal(t, "example.com/app", v)
+}
@@ -0,0 +1,319 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Constraints holds the validation keywords shared by properties, items, and parameters
+type Constraints struct {
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
+	MaxLength        *int64   `json:"maxLength,omitempty"`
+	MinLength        *int64   `json:"minLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	MaxItems         *int64   `json:"maxItems,omitempty"`
+	MinItems         *int64   `json:"minItems,omitempty"`
+	UniqueItems      bool     `json:"uniqueItems,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+}
+
+// The following struct tags are read by define to document the validation constraints of a property:
+//
+//	minimum:"1" maximum:"10" exclusiveMinimum:"true" exclusiveMaximum:"true" multipleOf:"2"
+//	minLength:"1" maxLength:"10" pattern:"^[a-z]+$"
+//	minItems:"1" maxItems:"10" uniqueItems:"true"
+//
+// For arrays, the numeric and string constraints apply to the innermost array items.  In addition, the commonly used
+// go-playground validator tag is understood e.g. validate:"min=1,max=10,oneof=a b c".  The meaning of min, max, len,
+// gt, gte, lt, and lte depends on the type of the property, and rules following dive apply to the array items or map
+// values.  Explicit constraint tags take precedence over the validate tag
+const (
+	tagMinimum          = "minimum"
+	tagMaximum          = "maximum"
+	tagExclusiveMinimum = "exclusiveMinimum"
+	tagExclusiveMaximum = "exclusiveMaximum"
+	tagMultipleOf       = "multipleOf"
+	tagMinLength        = "minLength"
+	tagMaxLength        = "maxLength"
+	tagPattern          = "pattern"
+	tagMinItems         = "minItems"
+	tagMaxItems         = "maxItems"
+	tagUniqueItems      = "uniqueItems"
+	tagValidate         = "validate"
+)
+
+// validateFormats maps go-playground validator rules onto their equivalent swagger format
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+// validatePatterns maps go-playground validator rules onto their equivalent swagger pattern
+var validatePatterns = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":   "^[0-9]+$",
+}
+
+// constraintTarget identifies the schema entity, either a property or its items, that constraints are applied to
+type constraintTarget struct {
+	typ    string
+	format *string
+	enum   *[]interface{}
+	*Constraints
+
+	// items and additionalProperties are the targets reached by dive
+	items                *Items
+	additionalProperties *Property
+}
+
+func propertyTarget(p *Property) constraintTarget {
+	return constraintTarget{
+		typ:                  p.Type,
+		format:               &p.Format,
+		enum:                 &p.Enum,
+		Constraints:          &p.Constraints,
+		items:                p.Items,
+		additionalProperties: p.AdditionalProperties,
+	}
+}
+
+func itemsTarget(items *Items) constraintTarget {
+	return constraintTarget{
+		typ:                  items.Type,
+		format:               &items.Format,
+		enum:                 &items.Enum,
+		Constraints:          &items.Constraints,
+		items:                items.Items,
+		additionalProperties: items.AdditionalProperties,
+	}
+}
+
+// leafItems returns the innermost items of the array property, p, e.g. the float64 items of [][]float64
+func leafItems(p *Property) *Items {
+	items := p.Items
+	for items.Type == "array" && items.Items != nil {
+		items = items.Items
+	}
+	return items
+}
+
+func float64Ptr(v string) *float64 {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+func int64Ptr(v string) *int64 {
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// bound applies the lower (or upper) bound, v, using the keyword appropriate to the type of the target
+func (t constraintTarget) bound(v string, lower, exclusive bool) {
+	switch t.typ {
+	case "integer", "number":
+		if lower {
+			t.Minimum, t.ExclusiveMinimum = float64Ptr(v), exclusive
+		} else {
+			t.Maximum, t.ExclusiveMaximum = float64Ptr(v), exclusive
+		}
+
+	case "string", "array":
+		n := int64Ptr(v)
+		if n != nil && exclusive {
+			if lower {
+				*n = *n + 1
+			} else {
+				*n = *n - 1
+			}
+		}
+
+		switch {
+		case t.typ == "string" && lower:
+			t.MinLength = n
+		case t.typ == "string":
+			t.MaxLength = n
+		case lower:
+			t.MinItems = n
+		default:
+			t.MaxItems = n
+		}
+	}
+}
+
+// splitOneOf splits the space separated values of the oneof rule; values containing spaces may be single quoted
+func splitOneOf(v string) []string {
+	var values []string
+	var value []rune
+	quoted := false
+
+	for _, r := range v {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if len(value) > 0 {
+				values = append(values, string(value))
+			}
+			value = value[:0]
+		default:
+			value = append(value, r)
+		}
+	}
+	if len(value) > 0 {
+		values = append(values, string(value))
+	}
+
+	return values
+}
+
+// applyRule applies a single go-playground validator rule e.g. min=1 to the target
+func (t constraintTarget) applyRule(rule string) {
+	name, param := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, param = rule[:i], rule[i+1:]
+	}
+
+	switch name {
+	case "min", "gte":
+		t.bound(param, true, false)
+	case "max", "lte":
+		t.bound(param, false, false)
+	case "gt":
+		t.bound(param, true, true)
+	case "lt":
+		t.bound(param, false, true)
+	case "len":
+		t.bound(param, true, false)
+		t.bound(param, false, false)
+	case "oneof":
+		values := []interface{}{}
+		for _, value := range splitOneOf(param) {
+			values = append(values, parseValue(t.typ, value))
+		}
+		*t.enum = values
+	case "unique":
+		t.UniqueItems = true
+	default:
+		if format, ok := validateFormats[name]; ok {
+			*t.format = format
+		} else if pattern, ok := validatePatterns[name]; ok {
+			t.Pattern = pattern
+		}
+	}
+}
+
+// applyValidate applies the go-playground validator tag, v, to the property
+func applyValidate(p *Property, v string) {
+	target := propertyTarget(p)
+	keys := false
+
+	for _, rule := range strings.Split(v, ",") {
+		rule = strings.TrimSpace(rule)
+
+		switch {
+		case rule == "keys":
+			// constraints on map keys cannot be expressed in swagger
+			keys = true
+
+		case rule == "endkeys":
+			keys = false
+
+		case keys:
+
+		case rule == "dive":
+			switch {
+			case target.items != nil:
+				target = itemsTarget(target.items)
+			case target.additionalProperties != nil:
+				target = propertyTarget(target.additionalProperties)
+			default:
+				return
+			}
+
+		case strings.Contains(rule, "|"):
+			// alternatives cannot be expressed as constraints
+
+		default:
+			target.applyRule(rule)
+		}
+	}
+}
+
+// applyConstraints customizes the property using the validation struct tags of the field
+func applyConstraints(p *Property, tag reflect.StructTag) {
+	if v := tag.Get(tagValidate); v != "" {
+		applyValidate(p, v)
+	}
+
+	target := propertyTarget(p)
+	if p.Type == "array" && p.Items != nil {
+		target = itemsTarget(leafItems(p))
+	}
+
+	if v := tag.Get(tagMinimum); v != "" {
+		target.Minimum = float64Ptr(v)
+	}
+	if v := tag.Get(tagMaximum); v != "" {
+		target.Maximum = float64Ptr(v)
+	}
+	if v := tag.Get(tagExclusiveMinimum); v != "" {
+		target.ExclusiveMinimum, _ = strconv.ParseBool(v)
+	}
+	if v := tag.Get(tagExclusiveMaximum); v != "" {
+		target.ExclusiveMaximum, _ = strconv.ParseBool(v)
+	}
+	if v := tag.Get(tagMultipleOf); v != "" {
+		target.MultipleOf = float64Ptr(v)
+	}
+	if v := tag.Get(tagMinLength); v != "" {
+		target.MinLength = int64Ptr(v)
+	}
+	if v := tag.Get(tagMaxLength); v != "" {
+		target.MaxLength = int64Ptr(v)
+	}
+	if v := tag.Get(tagPattern); v != "" {
+		target.Pattern = v
+	}
+
+	if v := tag.Get(tagMinItems); v != "" {
+		p.MinItems = int64Ptr(v)
+	}
+	if v := tag.Get(tagMaxItems); v != "" {
+		p.MaxItems = int64Ptr(v)
+	}
+	if v := tag.Get(tagUniqueItems); v != "" {
+		p.UniqueItems, _ = strconv.ParseBool(v)
+	}
+}
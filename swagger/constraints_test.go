@@ -0,0 +1,114 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Constrained struct {
+	Age      int               `json:"age" minimum:"0" maximum:"150" exclusiveMaximum:"true" multipleOf:"1"`
+	Name     string            `json:"name" minLength:"1" maxLength:"64" pattern:"^[a-z]+$"`
+	Tags     []string          `json:"tags" minItems:"1" maxItems:"5" uniqueItems:"true" maxLength:"10"`
+	Quantity int               `json:"quantity" validate:"required,min=1,max=10"`
+	Price    float64           `json:"price" validate:"gt=0,lt=1000"`
+	Code     string            `json:"code" validate:"len=3,alpha"`
+	Nick     string            `json:"nick" validate:"omitempty,gte=2,lte=8"`
+	Email    string            `json:"email" validate:"email"`
+	Status   string            `json:"status" validate:"oneof=active 'on hold' closed"`
+	Level    int               `json:"level" validate:"oneof=1 2 3"`
+	IDs      []string          `json:"ids" validate:"min=1,unique,dive,uuid4,max=36"`
+	Scores   map[string]int    `json:"scores" validate:"dive,min=0"`
+	Labels   map[string]string `json:"labels" validate:"dive,keys,alpha,endkeys,max=4"`
+	Either   string            `json:"either" validate:"email|url"`
+	Override int               `json:"override" validate:"min=1" minimum:"5"`
+}
+
+func TestConstraints(t *testing.T) {
+	obj := defineObject(Constrained{})
+
+	age := obj.Properties["age"].Constraints
+	assert.Equal(t, 0.0, *age.Minimum)
+	assert.Equal(t, 150.0, *age.Maximum)
+	assert.True(t, age.ExclusiveMaximum)
+	assert.False(t, age.ExclusiveMinimum)
+	assert.Equal(t, 1.0, *age.MultipleOf)
+
+	name := obj.Properties["name"].Constraints
+	assert.Equal(t, int64(1), *name.MinLength)
+	assert.Equal(t, int64(64), *name.MaxLength)
+	assert.Equal(t, "^[a-z]+$", name.Pattern)
+
+	tags := obj.Properties["tags"]
+	assert.Equal(t, int64(1), *tags.MinItems)
+	assert.Equal(t, int64(5), *tags.MaxItems)
+	assert.True(t, tags.UniqueItems)
+	assert.Nil(t, tags.MaxLength)
+	assert.Equal(t, int64(10), *tags.Items.MaxLength, "expected string constraints to apply to items")
+
+	quantity := obj.Properties["quantity"].Constraints
+	assert.Equal(t, 1.0, *quantity.Minimum)
+	assert.Equal(t, 10.0, *quantity.Maximum)
+
+	price := obj.Properties["price"].Constraints
+	assert.Equal(t, 0.0, *price.Minimum)
+	assert.True(t, price.ExclusiveMinimum)
+	assert.Equal(t, 1000.0, *price.Maximum)
+	assert.True(t, price.ExclusiveMaximum)
+
+	code := obj.Properties["code"].Constraints
+	assert.Equal(t, int64(3), *code.MinLength)
+	assert.Equal(t, int64(3), *code.MaxLength)
+	assert.Equal(t, "^[a-zA-Z]+$", code.Pattern)
+
+	nick := obj.Properties["nick"].Constraints
+	assert.Equal(t, int64(2), *nick.MinLength)
+	assert.Equal(t, int64(8), *nick.MaxLength)
+
+	assert.Equal(t, "email", obj.Properties["email"].Format)
+	assert.Equal(t, []interface{}{"active", "on hold", "closed"}, obj.Properties["status"].Enum)
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, obj.Properties["level"].Enum)
+
+	ids := obj.Properties["ids"]
+	assert.Equal(t, int64(1), *ids.MinItems)
+	assert.True(t, ids.UniqueItems)
+	assert.Nil(t, ids.MaxItems)
+	assert.Equal(t, "uuid", ids.Items.Format)
+	assert.Equal(t, int64(36), *ids.Items.MaxLength)
+
+	assert.Equal(t, 0.0, *obj.Properties["scores"].AdditionalProperties.Minimum)
+	labels := obj.Properties["labels"].AdditionalProperties
+	assert.Equal(t, "", labels.Pattern, "expected key constraints to be ignored")
+	assert.Equal(t, int64(4), *labels.MaxLength)
+
+	either := obj.Properties["either"]
+	assert.Equal(t, "", either.Format)
+	assert.Equal(t, "", either.Pattern)
+
+	assert.Equal(t, 5.0, *obj.Properties["override"].Minimum, "expected explicit tags to take precedence")
+}
+
+func TestConstraintsJSON(t *testing.T) {
+	type Model struct {
+		Age int `json:"age" minimum:"1" maximum:"10"`
+	}
+
+	data, err := json.Marshal(defineObject(Model{}).Properties["age"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"integer","format":"int32","minimum":1,"maximum":10}`, string(data))
+}
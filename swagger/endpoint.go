@@ -18,17 +18,38 @@ import "encoding/json"
 
 // Items represents items from the swagger doc
 type Items struct {
-	Type   string `json:"type,omitempty"`
-	Format string `json:"format,omitempty"`
-	Ref    string `json:"$ref,omitempty"`
+	Type   string        `json:"type,omitempty"`
+	Format string        `json:"format,omitempty"`
+	Ref    string        `json:"$ref,omitempty"`
+	Enum   []interface{} `json:"enum,omitempty"`
+	Constraints
+
+	// Items describes the elements of nested arrays e.g. [][]float64
+	Items *Items `json:"items,omitempty"`
+
+	// AdditionalProperties describes the values of arrays of maps e.g. []map[string]int
+	AdditionalProperties *Property `json:"additionalProperties,omitempty"`
+
+	// Required and Properties describe the elements of arrays of anonymous structs
+	Required   []string            `json:"required,omitempty"`
+	Properties map[string]Property `json:"properties,omitempty"`
 }
 
 // Schema represents a schema from the swagger doc
 type Schema struct {
 	Type      string      `json:"type,omitempty"`
+	Format    string      `json:"format,omitempty"`
 	Items     *Items      `json:"items,omitempty"`
 	Ref       string      `json:"$ref,omitempty"`
 	Prototype interface{} `json:"-"`
+
+	AdditionalProperties *Property `json:"additionalProperties,omitempty"`
+
+	// Description, Required, Properties, and AllOf describe inline schemas e.g. those of a parsed document
+	Description string              `json:"description,omitempty"`
+	Required    []string            `json:"required,omitempty"`
+	Properties  map[string]Property `json:"properties,omitempty"`
+	AllOf       []Property          `json:"allOf,omitempty"`
 }
 
 // Header represents a response header
@@ -40,6 +61,7 @@ type Header struct {
 
 // Response represents a response from the swagger doc
 type Response struct {
+	Ref         string            `json:"$ref,omitempty"`
 	Description string            `json:"description,omitempty"`
 	Schema      *Schema           `json:"schema,omitempty"`
 	Headers     map[string]Header `json:"headers,omitempty"`
@@ -47,13 +69,37 @@ type Response struct {
 
 // Parameter represents a parameter from the swagger doc
 type Parameter struct {
-	In          string  `json:"in,omitempty"`
-	Name        string  `json:"name,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Required    bool    `json:"required"`
-	Schema      *Schema `json:"schema,omitempty"`
-	Type        string  `json:"type,omitempty"`
-	Format      string  `json:"format,omitempty"`
+	Ref         string        `json:"$ref,omitempty"`
+	In          string        `json:"in,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Schema      *Schema       `json:"schema,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Items       *Items        `json:"items,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Constraints
+
+	// CollectionFormat determines how array parameters are delimited e.g. csv, ssv, tsv, pipes, or multi for repeated
+	// query and form parameters; defaults to csv
+	CollectionFormat string `json:"collectionFormat,omitempty"`
+}
+
+// marshalRef encodes a reference object; siblings of $ref are ignored by the spec and so are omitted
+func marshalRef(ref string) ([]byte, error) {
+	return json.Marshal(map[string]string{"$ref": ref})
+}
+
+// MarshalJSON encodes references to the parameters of the api as reference objects
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	if p.Ref != "" {
+		return marshalRef(p.Ref)
+	}
+
+	type document Parameter // prevents recursion into MarshalJSON
+	return json.Marshal(document(p))
 }
 
 // Endpoint represents an endpoint from the swagger doc
@@ -69,11 +115,46 @@ type Endpoint struct {
 	Handler     interface{}         `json:"-"`
 	Parameters  []Parameter         `json:"parameters,omitempty"`
 	Responses   map[string]Response `json:"responses,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
 
 	// swagger spec requires security to be an array of objects
 	Security *SecurityRequirement `json:"security,omitempty"`
 }
 
+// clone returns a copy of the endpoint whose parameters and responses may be modified without affecting e
+func (e *Endpoint) clone() *Endpoint {
+	v := *e
+
+	if e.Parameters != nil {
+		v.Parameters = make([]Parameter, len(e.Parameters))
+		copy(v.Parameters, e.Parameters)
+	}
+
+	if e.Responses != nil {
+		v.Responses = make(map[string]Response, len(e.Responses))
+		for code, response := range e.Responses {
+			v.Responses[code] = response
+		}
+	}
+
+	return &v
+}
+
+// MarshalJSON omits cookie parameters, which swagger 2.0 is unable to describe
+func (e *Endpoint) MarshalJSON() ([]byte, error) {
+	type document Endpoint // prevents recursion into MarshalJSON
+	v := document(*e)
+
+	v.Parameters = nil
+	for _, p := range e.Parameters {
+		if p.In != "cookie" {
+			v.Parameters = append(v.Parameters, p)
+		}
+	}
+
+	return json.Marshal(v)
+}
+
 type SecurityRequirement struct {
 	Requirements    []map[string][]string
 	DisableSecurity bool
@@ -0,0 +1,183 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field represents a single property of a json object along with the struct field that provides it
+type field struct {
+	name  string
+	tag   bool
+	index []int
+	typ   reflect.Type
+	field reflect.StructField
+}
+
+type byName []field
+
+func (f byName) Len() int      { return len(f) }
+func (f byName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f byName) Less(i, j int) bool {
+	if f[i].name != f[j].name {
+		return f[i].name < f[j].name
+	}
+	if len(f[i].index) != len(f[j].index) {
+		return len(f[i].index) < len(f[j].index)
+	}
+	if f[i].tag != f[j].tag {
+		return f[i].tag
+	}
+	return byIndex(f).Less(i, j)
+}
+
+type byIndex []field
+
+func (f byIndex) Len() int      { return len(f) }
+func (f byIndex) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f byIndex) Less(i, j int) bool {
+	for k, xk := range f[i].index {
+		if k >= len(f[j].index) {
+			return false
+		}
+		if xk != f[j].index[k] {
+			return xk < f[j].index[k]
+		}
+	}
+	return len(f[i].index) < len(f[j].index)
+}
+
+// fields returns the json properties of the struct type t.  Fields of embedded structs are promoted into the parent
+// and name conflicts are resolved following the same visibility rules as encoding/json
+func fields(t reflect.Type) []field {
+	var current []field
+	next := []field{{typ: t}}
+
+	// count of queued names for current level and the next
+	count := map[reflect.Type]int{}
+	nextCount := map[reflect.Type]int{}
+
+	// types already visited at an earlier level
+	visited := map[reflect.Type]bool{}
+
+	var found []field
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				unexported := sf.PkgPath != ""
+
+				if sf.Anonymous {
+					t := sf.Type
+					if t.Kind() == reflect.Ptr {
+						t = t.Elem()
+					}
+					// embedded structs may have exported fields even when the struct itself is unexported
+					if unexported && t.Kind() != reflect.Struct {
+						continue
+					}
+				} else if unexported {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name := strings.TrimSpace(strings.Split(tag, ",")[0])
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				// record found field; only untagged embedded structs are promoted
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					found = append(found, field{
+						name:  name,
+						tag:   tagged,
+						index: index,
+						typ:   ft,
+						field: sf,
+					})
+					if count[f.typ] > 1 {
+						// the same embedded struct appears more than once at this level; the duplicate annihilates
+						// the original so both are dropped by dominantField
+						found = append(found, found[len(found)-1])
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, field{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	sort.Sort(byName(found))
+
+	// delete all fields that are hidden by the go rules for embedded fields
+	out := found[:0]
+	for advance, i := 0, 0; i < len(found); i += advance {
+		name := found[i].name
+		for advance = 1; i+advance < len(found); advance++ {
+			if found[i+advance].name != name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, found[i])
+			continue
+		}
+		if dominant, ok := dominantField(found[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+
+	sort.Sort(byIndex(out))
+
+	return out
+}
+
+// dominantField looks through the fields, all of which are known to have the same name, to find the single field
+// that dominates the others; fields are sorted by depth and then by whether they were tagged
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tag == fields[1].tag {
+		return field{}, false
+	}
+	return fields[0], true
+}
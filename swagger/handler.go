@@ -0,0 +1,327 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultCacheSize is the number of host and scheme combinations for which the handler caches documents by default
+const defaultCacheSize = 64
+
+// HandlerOption provides additional customizations to the handler generated by API.Handler
+type HandlerOption func(h *handler)
+
+type handler struct {
+	openAPIVersion string
+	allowedHosts   map[string]bool
+	fixedHost      string
+	cacheSize      int
+
+	mux      sync.Mutex
+	cache    *lru
+	revision uint64
+}
+
+// OpenAPIVersion instructs the handler to serve the api as an OpenAPI 3.0 document with the specified version e.g.
+// 3.0.3 rather than as a swagger 2.0 document
+func OpenAPIVersion(version string) HandlerOption {
+	return func(h *handler) {
+		h.openAPIVersion = version
+	}
+}
+
+// AllowedHosts restricts the hosts the handler will customize the document for; requests for any other host are
+// served the host the api was configured with
+func AllowedHosts(hosts ...string) HandlerOption {
+	return func(h *handler) {
+		h.allowedHosts = map[string]bool{}
+		for _, host := range hosts {
+			h.allowedHosts[strings.ToLower(host)] = true
+		}
+	}
+}
+
+// FixedHost instructs the handler to serve the specified host regardless of the host requested
+func FixedHost(host string) HandlerOption {
+	return func(h *handler) {
+		h.fixedHost = host
+	}
+}
+
+// CacheSize sets the number of host and scheme combinations for which documents are cached; the least recently used
+// documents are discarded first.  A size of zero disables caching.  Defaults to 64
+func CacheSize(size int) HandlerOption {
+	return func(h *handler) {
+		h.cacheSize = size
+	}
+}
+
+// NoCache instructs the handler to render the document on every request
+func NoCache() HandlerOption {
+	return CacheSize(0)
+}
+
+// lru is a least recently used cache of rendered documents keyed by host and scheme
+type lru struct {
+	size     int
+	order    *list.List // most recently used first
+	elements map[string]*list.Element
+}
+
+type lruEntry struct {
+	key string
+	doc interface{}
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:     size,
+		order:    list.New(),
+		elements: map[string]*list.Element{},
+	}
+}
+
+func (c *lru) get(key string) (interface{}, bool) {
+	element, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).doc, true
+}
+
+func (c *lru) add(key string, doc interface{}) {
+	c.elements[key] = c.order.PushFront(&lruEntry{key: key, doc: doc})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.elements, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lru) len() int {
+	return c.order.Len()
+}
+
+// isScheme returns true if v is a scheme swagger is able to describe; other values are ignored as they are client
+// controlled
+func isScheme(v string) bool {
+	switch v {
+	case "http", "https", "ws", "wss":
+		return true
+	}
+	return false
+}
+
+// firstValue returns the first of the comma separated values of a header that proxies append to
+func firstValue(v string) string {
+	return strings.TrimSpace(strings.Split(v, ",")[0])
+}
+
+// forwarded returns the host and proto parameters of the first element of the RFC 7239 Forwarded header, v
+func forwarded(v string) (host, proto string) {
+	for _, pair := range strings.Split(firstValue(v), ";") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		value := strings.Trim(kv[1], `"`)
+		switch strings.ToLower(kv[0]) {
+		case "host":
+			host = value
+		case "proto":
+			proto = strings.ToLower(value)
+		}
+	}
+	return host, proto
+}
+
+// origin returns the host and scheme the client used to reach the api, taking proxies into account; configuredHost is
+// served to hosts that are not allowed
+func (h *handler) origin(configuredHost string, req *http.Request) (string, string) {
+	host := req.Host
+	scheme := ""
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	if v := firstValue(req.Header.Get("X-Forwarded-Host")); v != "" {
+		host = v
+	}
+	if v := firstValue(req.Header.Get("X-Forwarded-Proto")); isScheme(v) {
+		scheme = v
+	}
+	if v := req.Header.Get("Forwarded"); v != "" {
+		fHost, fProto := forwarded(v)
+		if fHost != "" {
+			host = fHost
+		}
+		if isScheme(fProto) {
+			scheme = fProto
+		}
+	}
+
+	if scheme == "" {
+		scheme = req.URL.Scheme
+	}
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	switch {
+	case h.fixedHost != "":
+		host = h.fixedHost
+	case h.allowedHosts != nil && !h.allowedHosts[strings.ToLower(host)]:
+		host = configuredHost
+	}
+
+	return host, scheme
+}
+
+// document returns the document to serve for the host and scheme, rendering the snapshot, api, if not already cached
+func (h *handler) document(api *API, revision uint64, host, scheme string) interface{} {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	key := host + ":" + scheme
+	if h.cache != nil {
+		if revision != h.revision {
+			// endpoints have been added or removed since the documents were cached
+			h.cache = newLRU(h.cacheSize)
+			h.revision = revision
+		}
+		if v, ok := h.cache.get(key); ok {
+			return v
+		}
+	}
+
+	api.Host = host
+	api.Schemes = []string{scheme}
+	var v interface{} = api
+
+	if h.openAPIVersion != "" {
+		doc := api.OpenAPI()
+		doc.OpenAPI = h.openAPIVersion
+		v = doc
+	}
+
+	if h.cache != nil {
+		h.cache.add(key, v)
+	}
+	return v
+}
+
+// Handler is a factory method that generates an http.HandlerFunc; if enableCors is true, then the handler will generate
+// cors headers.  The api is served as yaml when the request path ends in .yaml or .yml or when requested via the Accept
+// header and as json otherwise.  The host and scheme of the document are those used by the client, as reported by the
+// Forwarded, X-Forwarded-Host, and X-Forwarded-Proto headers when behind a proxy
+func (a *API) Handler(enableCors bool, options ...HandlerOption) http.HandlerFunc {
+	h := &handler{
+		cacheSize: defaultCacheSize,
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+	if h.cacheSize > 0 {
+		h.cache = newLRU(h.cacheSize)
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		asYAML := acceptsYAML(req)
+		if asYAML {
+			w.Header().Set("Content-Type", "application/yaml")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+
+		if enableCors {
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, api_key, Authorization")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, PUT")
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
+		api, revision := a.snapshot()
+		host, scheme := h.origin(api.Host, req)
+		v := h.document(api, revision, host, scheme)
+
+		// encode the document in full before writing so that errors may still be reported
+		var data []byte
+		var err error
+		if asYAML {
+			data, err = yaml.Marshal(v)
+		} else {
+			data, err = json.Marshal(v)
+			data = append(data, '\n')
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// quality returns the q-value of a single element of the Accept header; 1 if none is specified
+func quality(params []string) float64 {
+	for _, param := range params {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) != "q" {
+			continue
+		}
+		if q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+			return q
+		}
+	}
+	return 1
+}
+
+// acceptsYAML returns true if the request path ends in .yaml or .yml or if the Accept header prefers yaml to json.
+// Media types are ranked by their q-values, with ties resolved in favor of the type listed first
+func acceptsYAML(req *http.Request) bool {
+	if ext := path.Ext(req.URL.Path); ext == ".yaml" || ext == ".yml" {
+		return true
+	}
+
+	yamlQ, jsonQ := -1.0, -1.0
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		parts := strings.Split(accept, ";")
+		q := quality(parts[1:])
+
+		switch strings.TrimSpace(parts[0]) {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			if q > yamlQ && q > jsonQ {
+				yamlQ = q
+			}
+		case "application/json", "*/*":
+			if q > jsonQ && q > yamlQ {
+				jsonQ = q
+			}
+		}
+	}
+
+	return yamlQ > 0 && yamlQ > jsonQ
+}
@@ -0,0 +1,151 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrigin(t *testing.T) {
+	testCases := map[string]struct {
+		Options []HandlerOption
+		Headers map[string]string
+		TLS     bool
+		Host    string
+		Scheme  string
+	}{
+		"request": {
+			Host:   "example.com",
+			Scheme: "http",
+		},
+		"tls": {
+			TLS:    true,
+			Host:   "example.com",
+			Scheme: "https",
+		},
+		"x-forwarded": {
+			Headers: map[string]string{"X-Forwarded-Host": "api.example.com, proxy", "X-Forwarded-Proto": "https"},
+			Host:    "api.example.com",
+			Scheme:  "https",
+		},
+		"invalid proto": {
+			Headers: map[string]string{"X-Forwarded-Proto": "gopher"},
+			Host:    "example.com",
+			Scheme:  "http",
+		},
+		"forwarded": {
+			Headers: map[string]string{
+				"Forwarded":        `for=192.0.2.60;proto=HTTPS;host="api.example.com:8443", for=198.51.100.17`,
+				"X-Forwarded-Host": "ignored.example.com",
+			},
+			Host:   "api.example.com:8443",
+			Scheme: "https",
+		},
+		"fixed host": {
+			Options: []HandlerOption{FixedHost("fixed.example.com")},
+			Headers: map[string]string{"X-Forwarded-Host": "api.example.com"},
+			Host:    "fixed.example.com",
+			Scheme:  "http",
+		},
+		"allowed host": {
+			Options: []HandlerOption{AllowedHosts("API.example.com")},
+			Headers: map[string]string{"X-Forwarded-Host": "api.example.com"},
+			Host:    "api.example.com",
+			Scheme:  "http",
+		},
+		"disallowed host": {
+			Options: []HandlerOption{AllowedHosts("api.example.com")},
+			Host:    "configured.example.com",
+			Scheme:  "http",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			h := &handler{}
+			for _, opt := range tc.Options {
+				opt(h)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/swagger", nil)
+			req.Host = "example.com"
+			for k, v := range tc.Headers {
+				req.Header.Set(k, v)
+			}
+			if tc.TLS {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			host, scheme := h.origin("configured.example.com", req)
+			assert.Equal(t, tc.Host, host)
+			assert.Equal(t, tc.Scheme, scheme)
+		})
+	}
+}
+
+func TestLRU(t *testing.T) {
+	c := newLRU(2)
+	c.add("a", 1)
+	c.add("b", 2)
+
+	_, ok := c.get("a")
+	assert.True(t, ok)
+
+	c.add("c", 3)
+	assert.Equal(t, 2, c.len())
+
+	_, ok = c.get("b")
+	assert.False(t, ok, "expected the least recently used document to be discarded")
+	v, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+}
+
+func TestHandlerCache(t *testing.T) {
+	api := &API{Swagger: "2.0", Host: "configured.example.com"}
+
+	request := func(h http.HandlerFunc, host string) string {
+		req, _ := http.NewRequest(http.MethodGet, "/swagger", nil)
+		req.Host = host
+		w := httptest.NewRecorder()
+		h(w, req)
+
+		doc := map[string]interface{}{}
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(&doc))
+		return doc["host"].(string)
+	}
+
+	h := &handler{cacheSize: 2, cache: newLRU(2)}
+	for i := 0; i < 10; i++ {
+		snapshot, revision := api.snapshot()
+		h.document(snapshot, revision, "host"+strconv.Itoa(i), "http")
+	}
+	assert.Equal(t, 2, h.cache.len(), "expected the cache to be bounded")
+
+	uncached := api.Handler(false, NoCache())
+	assert.Equal(t, "a.example.com", request(uncached, "a.example.com"))
+	assert.Equal(t, "b.example.com", request(uncached, "b.example.com"))
+
+	restricted := api.Handler(false, AllowedHosts("a.example.com"), CacheSize(1))
+	assert.Equal(t, "a.example.com", request(restricted, "a.example.com"))
+	assert.Equal(t, "configured.example.com", request(restricted, "random.example.com"))
+}
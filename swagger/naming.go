@@ -0,0 +1,123 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NamingStrategy returns the definition name for the go type, t
+type NamingStrategy func(t reflect.Type) string
+
+// Namer may be implemented by types that wish to choose the name of their own definition.  SwaggerName is invoked on
+// the zero value of the type
+type Namer interface {
+	SwaggerName() string
+}
+
+var namerType = reflect.TypeOf((*Namer)(nil)).Elem()
+
+var namePathReplacer = strings.NewReplacer("/", ".", "-", "_")
+
+// namePattern matches the definition names that may be used, unescaped, in a $ref
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// isValidName returns true if name may name a definition
+func isValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// ShortNames names definitions after the go type alone e.g. User
+func ShortNames(t reflect.Type) string {
+	return t.Name()
+}
+
+// PackageNames names definitions after the last element of the package path and the go type e.g. modelsUser; this
+// is the default naming strategy
+func PackageNames(t reflect.Type) string {
+	return makeName(t)
+}
+
+// FullPathNames names definitions after the complete package path and the go type e.g. github.com.acme.models.User
+func FullPathNames(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+	return namePathReplacer.Replace(t.PkgPath()) + "." + t.Name()
+}
+
+// preferredName returns the name t would like to have; collisions have not yet been taken into account
+func (r *reflector) preferredName(t reflect.Type) string {
+	if t.Implements(namerType) {
+		return reflect.Zero(t).Interface().(Namer).SwaggerName()
+	}
+	if reflect.PtrTo(t).Implements(namerType) {
+		return reflect.New(t).Interface().(Namer).SwaggerName()
+	}
+
+	if r.Naming != nil {
+		return r.Naming(t)
+	}
+	return PackageNames(t)
+}
+
+// reserve prevents name from being assigned to any go type e.g. as it names a definition parsed from a document
+func (r *reflector) reserve(name string) {
+	if r.types == nil {
+		r.names = map[reflect.Type]string{}
+		r.types = map[string]reflect.Type{}
+	}
+	if _, taken := r.types[name]; !taken {
+		r.types[name] = nil
+	}
+}
+
+// name returns the unique definition name for t.  When two types would otherwise share a name, the type seen later
+// is qualified by its full package path and, should that still collide, by a numeric suffix.  Types whose preferred
+// and full path names are both empty or otherwise invalid e.g. anonymous structs are named object
+func (r *reflector) name(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	if r.names == nil {
+		r.names = map[reflect.Type]string{}
+		r.types = map[string]reflect.Type{}
+	}
+
+	name := r.preferredName(t)
+	if _, taken := r.types[name]; taken || !isValidName(name) {
+		name = FullPathNames(t)
+	}
+	if !isValidName(name) {
+		name = "object"
+	}
+	if _, taken := r.types[name]; taken {
+		base := name
+		for i := 2; ; i++ {
+			name = base + strconv.Itoa(i)
+			if _, taken := r.types[name]; !taken {
+				break
+			}
+		}
+	}
+
+	r.names[t] = name
+	r.types[name] = t
+	return name
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Renamed struct {
+	Value string
+}
+
+func (Renamed) SwaggerName() string {
+	return "CustomName"
+}
+
+func userA() reflect.Type {
+	type User struct {
+		A string
+	}
+	return reflect.TypeOf(User{})
+}
+
+func userB() reflect.Type {
+	type User struct {
+		B string
+	}
+	return reflect.TypeOf(User{})
+}
+
+func TestNamingStrategies(t *testing.T) {
+	typ := reflect.TypeOf(Person{})
+	assert.Equal(t, "Person", ShortNames(typ))
+	assert.Equal(t, "swaggerPerson", PackageNames(typ))
+	assert.Equal(t, "github.com.savaki.swag.swagger.Person", FullPathNames(typ))
+	assert.Equal(t, "int", FullPathNames(reflect.TypeOf(0)))
+}
+
+func TestNameCollision(t *testing.T) {
+	r := &reflector{}
+	a, b := userA(), userB()
+
+	assert.Equal(t, "swaggerUser", r.name(a))
+	assert.Equal(t, "github.com.savaki.swag.swagger.User", r.name(b))
+	assert.Equal(t, "swaggerUser", r.name(a), "expected names to be stable")
+
+	r = &reflector{SchemaOptions: SchemaOptions{Naming: FullPathNames}}
+	assert.Equal(t, "github.com.savaki.swag.swagger.User", r.name(a))
+	assert.Equal(t, "github.com.savaki.swag.swagger.User2", r.name(b))
+
+	r = &reflector{}
+	r.reserve("swaggerUser")
+	assert.Equal(t, "github.com.savaki.swag.swagger.User", r.name(a), "expected reserved names to be avoided")
+}
+
+func TestNameCollisionDefine(t *testing.T) {
+	r := &reflector{}
+	first := r.define(userA())
+	second := r.define(userB())
+	assert.Contains(t, first, "swaggerUser")
+	assert.Contains(t, second, "github.com.savaki.swag.swagger.User")
+
+	schema := r.makeSchema(userB())
+	assert.Equal(t, "#/definitions/github.com.savaki.swag.swagger.User", schema.Ref)
+}
+
+func TestNamer(t *testing.T) {
+	type Holder struct {
+		Renamed Renamed `json:"renamed"`
+	}
+
+	r := &reflector{SchemaOptions: SchemaOptions{Naming: ShortNames}}
+	v := r.define(Holder{})
+	assert.Contains(t, v, "Holder")
+	assert.Contains(t, v, "CustomName")
+	assert.Equal(t, "#/definitions/CustomName", v["Holder"].Properties["renamed"].Ref)
+}
+
+func TestNamingDeterministic(t *testing.T) {
+	a, b := userA(), userB()
+	parent := reflect.StructOf([]reflect.StructField{
+		{Name: "Z", Type: a, Tag: `json:"z"`},
+		{Name: "M", Type: reflect.SliceOf(b), Tag: `json:"m"`},
+	})
+
+	for i := 0; i < 10; i++ {
+		r := &reflector{}
+		v := r.define(parent)
+		assert.Contains(t, v, "swaggerUser")
+		assert.Contains(t, v, "github.com.savaki.swag.swagger.User")
+		assert.Equal(t, "swaggerUser", r.name(a))
+	}
+}
+
+type Unnamed struct{}
+
+func (Unnamed) SwaggerName() string {
+	return ""
+}
+
+func TestNameInvalid(t *testing.T) {
+	r := &reflector{}
+	anonymous := reflect.TypeOf(struct{ A string }{})
+	assert.Equal(t, "object", r.name(anonymous))
+	assert.Equal(t, "object2", r.name(reflect.TypeOf(struct{ B string }{})))
+	assert.Equal(t, "github.com.savaki.swag.swagger.Unnamed", r.name(reflect.TypeOf(Unnamed{})))
+	assert.Equal(t, "object", r.name(anonymous), "expected names to be stable")
+
+	for _, name := range r.names {
+		assert.True(t, isValidName(name), name)
+	}
+	assert.False(t, isValidName(""))
+	assert.False(t, isValidName("."))
+	assert.False(t, isValidName("2"))
+}
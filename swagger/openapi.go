@@ -0,0 +1,692 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	// DefaultOpenAPIVersion is the version reported by documents generated via API.OpenAPI
+	DefaultOpenAPIVersion = "3.0.3"
+
+	definitionsPrefix = "#/definitions/"
+	schemasPrefix     = "#/components/schemas/"
+
+	componentParametersPrefix = "#/components/parameters/"
+	componentResponsesPrefix  = "#/components/responses/"
+)
+
+// OpenAPI represents the top level OpenAPI 3.0 document
+type OpenAPI struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+	Security   *SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+}
+
+// Server represents a server entity from the OpenAPI 3.0 document
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds the reusable entities of the OpenAPI 3.0 document
+type Components struct {
+	Schemas         map[string]*OpenAPISchema        `json:"schemas,omitempty"`
+	Parameters      map[string]OpenAPIParameter      `json:"parameters,omitempty"`
+	Responses       map[string]OpenAPIResponse       `json:"responses,omitempty"`
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// PathItem holds the operations available on a single path
+type PathItem struct {
+	Delete  *Operation `json:"delete,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Get     *Operation `json:"get,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty"`
+}
+
+// Operation represents a single api operation on a path
+type Operation struct {
+	Tags        []string                   `json:"tags,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	OperationID string                     `json:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Security    *SecurityRequirement       `json:"security,omitempty"`
+}
+
+// OpenAPIParameter represents a non-body parameter from the OpenAPI 3.0 document
+type OpenAPIParameter struct {
+	Ref         string         `json:"$ref,omitempty"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+	Style       string         `json:"style,omitempty"`
+	Explode     *bool          `json:"explode,omitempty"`
+}
+
+// MarshalJSON encodes references to the parameter components as reference objects
+func (p OpenAPIParameter) MarshalJSON() ([]byte, error) {
+	if p.Ref != "" {
+		return marshalRef(p.Ref)
+	}
+
+	type document OpenAPIParameter // prevents recursion into MarshalJSON
+	return json.Marshal(document(p))
+}
+
+// RequestBody describes the body of a request; replaces the swagger 2.0 body parameter
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType provides the schema for a given content type
+type MediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIResponse represents a response from the OpenAPI 3.0 document
+type OpenAPIResponse struct {
+	Ref         string                   `json:"$ref,omitempty"`
+	Description string                   `json:"description"`
+	Headers     map[string]OpenAPIHeader `json:"headers,omitempty"`
+	Content     map[string]MediaType     `json:"content,omitempty"`
+}
+
+// MarshalJSON encodes references to the response components as reference objects
+func (r OpenAPIResponse) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		return marshalRef(r.Ref)
+	}
+
+	type document OpenAPIResponse // prevents recursion into MarshalJSON
+	return json.Marshal(document(r))
+}
+
+// OpenAPIHeader represents a response header from the OpenAPI 3.0 document
+type OpenAPIHeader struct {
+	Description string         `json:"description,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPISchema represents a schema from the OpenAPI 3.0 document.  Unlike swagger 2.0, a single schema type is used
+// for definitions, properties, and items
+type OpenAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Enum        []interface{}             `json:"enum,omitempty"`
+	Example     interface{}               `json:"example,omitempty"`
+	Default     interface{}               `json:"default,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+	Items       *OpenAPISchema            `json:"items,omitempty"`
+	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Nullable    bool                      `json:"nullable,omitempty"`
+	ReadOnly    bool                      `json:"readOnly,omitempty"`
+	WriteOnly   bool                      `json:"writeOnly,omitempty"`
+	Constraints
+
+	AdditionalProperties *OpenAPISchema        `json:"additionalProperties,omitempty"`
+	AllOf                []*OpenAPISchema      `json:"allOf,omitempty"`
+	Discriminator        *OpenAPIDiscriminator `json:"discriminator,omitempty"`
+}
+
+// OpenAPIDiscriminator represents the discriminator of a polymorphic schema
+type OpenAPIDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// OpenAPISecurityScheme represents a security scheme from the OpenAPI 3.0 document
+type OpenAPISecurityScheme struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	In          string      `json:"in,omitempty"`
+	Scheme      string      `json:"scheme,omitempty"`
+	Flows       *OAuthFlows `json:"flows,omitempty"`
+}
+
+// OAuthFlows holds the configuration for each of the supported oauth2 flows
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow represents the configuration of a single oauth2 flow
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// openAPIRef converts a swagger 2.0 definitions reference into its components equivalent
+func openAPIRef(ref string) string {
+	switch {
+	case strings.HasPrefix(ref, definitionsPrefix):
+		return schemasPrefix + ref[len(definitionsPrefix):]
+	case strings.HasPrefix(ref, parametersPrefix):
+		return componentParametersPrefix + ref[len(parametersPrefix):]
+	case strings.HasPrefix(ref, responsesPrefix):
+		return componentResponsesPrefix + ref[len(responsesPrefix):]
+	}
+	return ref
+}
+
+func openAPIItems(items *Items) *OpenAPISchema {
+	if items == nil {
+		return nil
+	}
+
+	return &OpenAPISchema{
+		Ref:         openAPIRef(items.Ref),
+		Type:        items.Type,
+		Format:      items.Format,
+		Enum:        items.Enum,
+		Constraints: items.Constraints,
+		Items:       openAPIItems(items.Items),
+		Required:    items.Required,
+		Properties:  openAPIProperties(items.Properties),
+
+		AdditionalProperties: openAPIProperty(items.AdditionalProperties),
+	}
+}
+
+func openAPIProperties(properties map[string]Property) map[string]*OpenAPISchema {
+	if properties == nil {
+		return nil
+	}
+
+	schemas := map[string]*OpenAPISchema{}
+	for name, p := range properties {
+		p := p
+		schemas[name] = openAPIProperty(&p)
+	}
+	return schemas
+}
+
+func openAPIProperty(p *Property) *OpenAPISchema {
+	if p == nil {
+		return nil
+	}
+
+	s := &OpenAPISchema{
+		Ref:                  openAPIRef(p.Ref),
+		Type:                 p.Type,
+		Format:               p.Format,
+		Description:          p.Description,
+		Enum:                 p.Enum,
+		Example:              p.Example,
+		Default:              p.Default,
+		Nullable:             p.Nullable,
+		ReadOnly:             p.ReadOnly,
+		WriteOnly:            p.WriteOnly,
+		Constraints:          p.Constraints,
+		Items:                openAPIItems(p.Items),
+		Required:             p.Required,
+		Properties:           openAPIProperties(p.Properties),
+		AdditionalProperties: openAPIProperty(p.AdditionalProperties),
+	}
+
+	for _, child := range p.AllOf {
+		child := child
+		s.AllOf = append(s.AllOf, openAPIProperty(&child))
+	}
+
+	return s
+}
+
+func openAPIObject(obj Object) *OpenAPISchema {
+	s := &OpenAPISchema{
+		Ref:                  openAPIRef(obj.Ref),
+		Type:                 obj.Type,
+		Format:               obj.Format,
+		Description:          obj.Description,
+		Required:             obj.Required,
+		Items:                openAPIItems(obj.Items),
+		Enum:                 obj.Enum,
+		Example:              obj.Example,
+		AdditionalProperties: openAPIProperty(obj.AdditionalProperties),
+	}
+
+	s.Properties = openAPIProperties(obj.Properties)
+
+	for _, child := range obj.AllOf {
+		s.AllOf = append(s.AllOf, openAPIObject(child))
+	}
+
+	if obj.Discriminator != "" {
+		s.Discriminator = &OpenAPIDiscriminator{
+			PropertyName: obj.Discriminator,
+		}
+		if len(obj.DiscriminatorMapping) > 0 {
+			s.Discriminator.Mapping = map[string]string{}
+			for value, name := range obj.DiscriminatorMapping {
+				s.Discriminator.Mapping[value] = schemasPrefix + name
+			}
+		}
+	}
+
+	return s
+}
+
+func openAPISchema(schema *Schema) *OpenAPISchema {
+	if schema == nil {
+		return nil
+	}
+
+	s := &OpenAPISchema{
+		Ref:                  openAPIRef(schema.Ref),
+		Type:                 schema.Type,
+		Format:               schema.Format,
+		Description:          schema.Description,
+		Required:             schema.Required,
+		Items:                openAPIItems(schema.Items),
+		Properties:           openAPIProperties(schema.Properties),
+		AdditionalProperties: openAPIProperty(schema.AdditionalProperties),
+	}
+
+	for _, child := range schema.AllOf {
+		child := child
+		s.AllOf = append(s.AllOf, openAPIProperty(&child))
+	}
+
+	return s
+}
+
+func openAPIContent(mediaTypes []string, schema *OpenAPISchema) map[string]MediaType {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+
+	content := map[string]MediaType{}
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = MediaType{Schema: schema}
+	}
+	return content
+}
+
+// openAPIParameterSchema describes the type of the non-body parameter, p
+func openAPIParameterSchema(p Parameter) *OpenAPISchema {
+	return &OpenAPISchema{
+		Type:    p.Type,
+		Format:  p.Format,
+		Enum:    p.Enum,
+		Default: p.Default,
+		Items:   openAPIItems(p.Items),
+
+		Constraints: p.Constraints,
+	}
+}
+
+// openAPIStyle converts the collectionFormat of an array parameter into the equivalent OpenAPI 3.0 style and explode.
+// The swagger 2.0 default, csv, differs from the OpenAPI 3.0 default for query and cookie parameters
+func openAPIStyle(in, collectionFormat string) (string, *bool) {
+	explode := func(v bool) *bool { return &v }
+
+	switch collectionFormat {
+	case "multi":
+		return "form", explode(true)
+	case "ssv":
+		return "spaceDelimited", explode(false)
+	case "pipes":
+		return "pipeDelimited", explode(false)
+	}
+
+	// csv; tsv has no OpenAPI 3.0 equivalent
+	switch in {
+	case "query", "cookie":
+		return "form", explode(false)
+	}
+	return "", nil
+}
+
+// formMediaTypes returns the form media types within consumes.  If there are none, multipart/form-data is returned for
+// forms that upload files and application/x-www-form-urlencoded otherwise
+func formMediaTypes(consumes []string, multipart bool) []string {
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+
+	if len(mediaTypes) == 0 {
+		if multipart {
+			return []string{"multipart/form-data"}
+		}
+		return []string{"application/x-www-form-urlencoded"}
+	}
+	return mediaTypes
+}
+
+func openAPISecurityScheme(s SecurityScheme) OpenAPISecurityScheme {
+	scheme := OpenAPISecurityScheme{
+		Type:        s.Type,
+		Description: s.Description,
+		Name:        s.Name,
+		In:          s.In,
+	}
+
+	switch s.Type {
+	case "basic":
+		scheme.Type = "http"
+		scheme.Scheme = "basic"
+
+	case "oauth2":
+		scopes := s.Scopes
+		if scopes == nil {
+			scopes = map[string]string{}
+		}
+
+		scheme.Flows = &OAuthFlows{}
+		switch s.Flow {
+		case "implicit":
+			scheme.Flows.Implicit = &OAuthFlow{AuthorizationURL: s.AuthorizationURL, Scopes: scopes}
+		case "password":
+			scheme.Flows.Password = &OAuthFlow{TokenURL: s.TokenURL, Scopes: scopes}
+		case "application":
+			scheme.Flows.ClientCredentials = &OAuthFlow{TokenURL: s.TokenURL, Scopes: scopes}
+		case "accessCode":
+			scheme.Flows.AuthorizationCode = &OAuthFlow{AuthorizationURL: s.AuthorizationURL, TokenURL: s.TokenURL, Scopes: scopes}
+		}
+	}
+
+	return scheme
+}
+
+// openAPIParameter converts the non-body, non-form parameter, p
+func openAPIParameter(p Parameter) OpenAPIParameter {
+	param := OpenAPIParameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.Required || p.In == "path",
+		Schema:      openAPIParameterSchema(p),
+	}
+	if p.Type == "array" {
+		param.Style, param.Explode = openAPIStyle(p.In, p.CollectionFormat)
+	}
+	return param
+}
+
+// openAPIResponse converts the response, r, whose body is encoded using one of the produces media types
+func openAPIResponse(r Response, produces []string) OpenAPIResponse {
+	if r.Ref != "" {
+		return OpenAPIResponse{Ref: openAPIRef(r.Ref)}
+	}
+
+	response := OpenAPIResponse{
+		Description: r.Description,
+	}
+
+	if r.Schema != nil {
+		response.Content = openAPIContent(produces, openAPISchema(r.Schema))
+	}
+
+	if r.Headers != nil {
+		response.Headers = map[string]OpenAPIHeader{}
+		for name, h := range r.Headers {
+			response.Headers[name] = OpenAPIHeader{
+				Description: h.Description,
+				Schema: &OpenAPISchema{
+					Type:   h.Type,
+					Format: h.Format,
+				},
+			}
+		}
+	}
+
+	return response
+}
+
+// isComponent returns true if the api parameter, p, may be a parameter component; OpenAPI 3.0 describes body and form
+// parameters using request bodies instead
+func isComponent(p Parameter) bool {
+	return p.In != "body" && p.In != "formData"
+}
+
+// inherit returns the parameters of an endpoint, own, preceded by the path parameters, shared, that it does not
+// override.  Parameters are identified by name and location; references are resolved using the api parameters
+func inherit(shared, own []Parameter, parameters map[string]Parameter) []Parameter {
+	if len(shared) == 0 {
+		return own
+	}
+
+	key := func(p Parameter) string {
+		if p.Ref != "" {
+			if global, ok := parameters[strings.TrimPrefix(p.Ref, parametersPrefix)]; ok {
+				p = global
+			}
+		}
+		return p.In + "/" + p.Name
+	}
+
+	overridden := map[string]bool{}
+	for _, p := range own {
+		overridden[key(p)] = true
+	}
+
+	var inherited []Parameter
+	for _, p := range shared {
+		if !overridden[key(p)] {
+			inherited = append(inherited, p)
+		}
+	}
+	return append(inherited, own...)
+}
+
+// openAPIOperation converts the endpoint, e, which inherits the path parameters, shared; references to the api
+// parameters that are not components are replaced by the parameters themselves
+func openAPIOperation(e *Endpoint, shared []Parameter, parameters map[string]Parameter) *Operation {
+	op := &Operation{
+		Tags:        e.Tags,
+		Summary:     e.Summary,
+		Description: e.Description,
+		OperationID: e.OperationID,
+		Responses:   map[string]OpenAPIResponse{},
+		Deprecated:  e.Deprecated,
+		Security:    e.Security,
+	}
+
+	var form *OpenAPISchema
+	formRequired, multipart := false, false
+
+	for _, p := range inherit(shared, e.Parameters, parameters) {
+		if p.Ref != "" {
+			global, ok := parameters[strings.TrimPrefix(p.Ref, parametersPrefix)]
+			if !ok || isComponent(global) {
+				op.Parameters = append(op.Parameters, OpenAPIParameter{Ref: openAPIRef(p.Ref)})
+				continue
+			}
+			p = global
+		}
+
+		if p.In == "body" {
+			op.RequestBody = &RequestBody{
+				Description: p.Description,
+				Required:    p.Required,
+				Content:     openAPIContent(e.Consumes, openAPISchema(p.Schema)),
+			}
+			continue
+		}
+
+		// form parameters become the properties of a form request body
+		if p.In == "formData" {
+			if form == nil {
+				form = &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+			}
+
+			property := openAPIParameterSchema(p)
+			property.Description = p.Description
+			if p.Type == "file" {
+				property.Type, property.Format = "string", "binary"
+				multipart = true
+			}
+			form.Properties[p.Name] = property
+
+			if p.Required {
+				form.Required = append(form.Required, p.Name)
+				formRequired = true
+			}
+			continue
+		}
+
+		op.Parameters = append(op.Parameters, openAPIParameter(p))
+	}
+
+	if form != nil && op.RequestBody == nil {
+		op.RequestBody = &RequestBody{
+			Required: formRequired,
+			Content:  openAPIContent(formMediaTypes(e.Consumes, multipart), form),
+		}
+	}
+
+	for code, r := range e.Responses {
+		op.Responses[code] = openAPIResponse(r, e.Produces)
+	}
+
+	return op
+}
+
+// openAPIPathItem converts the endpoints, e.  The path parameters are listed by each operation that inherits them, as
+// shared body and form parameters become the request body of each operation
+func openAPIPathItem(e *Endpoints, parameters map[string]Parameter) *PathItem {
+	item := &PathItem{}
+	if e.Delete != nil {
+		item.Delete = openAPIOperation(e.Delete, e.Parameters, parameters)
+	}
+	if e.Head != nil {
+		item.Head = openAPIOperation(e.Head, e.Parameters, parameters)
+	}
+	if e.Get != nil {
+		item.Get = openAPIOperation(e.Get, e.Parameters, parameters)
+	}
+	if e.Options != nil {
+		item.Options = openAPIOperation(e.Options, e.Parameters, parameters)
+	}
+	if e.Post != nil {
+		item.Post = openAPIOperation(e.Post, e.Parameters, parameters)
+	}
+	if e.Put != nil {
+		item.Put = openAPIOperation(e.Put, e.Parameters, parameters)
+	}
+	if e.Patch != nil {
+		item.Patch = openAPIOperation(e.Patch, e.Parameters, parameters)
+	}
+	if e.Trace != nil {
+		item.Trace = openAPIOperation(e.Trace, e.Parameters, parameters)
+	}
+	return item
+}
+
+// servers converts host, basePath, and schemes into the equivalent list of OpenAPI 3.0 servers
+func (a *API) servers() []Server {
+	basePath := strings.TrimSuffix(a.BasePath, "/")
+
+	if a.Host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []Server{{URL: basePath}}
+	}
+
+	if len(a.Schemes) == 0 {
+		return []Server{{URL: "//" + a.Host + basePath}}
+	}
+
+	servers := make([]Server, 0, len(a.Schemes))
+	for _, scheme := range a.Schemes {
+		servers = append(servers, Server{URL: scheme + "://" + a.Host + basePath})
+	}
+	return servers
+}
+
+// OpenAPI renders the swagger 2.0 api as an OpenAPI 3.0 document.  Body parameters are converted into request
+// bodies, produces and consumes into content types, definitions into components, and host, basePath, and schemes into
+// servers
+func (a *API) OpenAPI() *OpenAPI {
+	a, _ = a.snapshot()
+
+	doc := &OpenAPI{
+		OpenAPI:  DefaultOpenAPIVersion,
+		Info:     a.Info,
+		Servers:  a.servers(),
+		Paths:    map[string]*PathItem{},
+		Security: a.Security,
+		Tags:     a.Tags,
+	}
+
+	for p, endpoints := range a.Paths {
+		doc.Paths[p] = openAPIPathItem(endpoints, a.Parameters)
+	}
+
+	if len(a.Definitions) > 0 || len(a.Parameters) > 0 || len(a.Responses) > 0 || len(a.SecurityDefinitions) > 0 {
+		doc.Components = &Components{}
+	}
+
+	if len(a.Definitions) > 0 {
+		doc.Components.Schemas = map[string]*OpenAPISchema{}
+		for name, obj := range a.Definitions {
+			doc.Components.Schemas[name] = openAPIObject(obj)
+		}
+	}
+
+	for name, p := range a.Parameters {
+		if !isComponent(p) {
+			continue
+		}
+		if doc.Components.Parameters == nil {
+			doc.Components.Parameters = map[string]OpenAPIParameter{}
+		}
+		doc.Components.Parameters[name] = openAPIParameter(p)
+	}
+
+	if len(a.Responses) > 0 {
+		doc.Components.Responses = map[string]OpenAPIResponse{}
+		for name, r := range a.Responses {
+			doc.Components.Responses[name] = openAPIResponse(r, nil)
+		}
+	}
+
+	if len(a.SecurityDefinitions) > 0 {
+		doc.Components.SecuritySchemes = map[string]OpenAPISecurityScheme{}
+		for name, scheme := range a.SecurityDefinitions {
+			doc.Components.SecuritySchemes[name] = openAPISecurityScheme(scheme)
+		}
+	}
+
+	return doc
+}
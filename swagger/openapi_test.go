@@ -0,0 +1,217 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/savaki/swag"
+	"github.com/savaki/swag/endpoint"
+	"github.com/savaki/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Category struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Animal struct {
+	ID       int64    `json:"id"`
+	Category Category `json:"category"`
+	Tags     []string `json:"tags"`
+}
+
+func petstore() *swagger.API {
+	post := endpoint.New("post", "/pet", "Add a new pet to the store",
+		endpoint.Body(Animal{}, "Pet object that needs to be added to the store", true),
+		endpoint.Response(http.StatusOK, Animal{}, "Successfully added pet",
+			endpoint.Header("X-Rate-Limit", "integer", "int32", "calls per hour allowed by the user"),
+		),
+		endpoint.Consumes("application/json", "application/xml"),
+	)
+	get := endpoint.New("get", "/pet/{petId}", "Find pet by ID",
+		endpoint.Path("petId", "integer", "ID of pet to return", true),
+		endpoint.Query("verbose", "boolean", "include details", false),
+		endpoint.Response(http.StatusOK, Animal{}, "successful operation"),
+		endpoint.Security("petstore_auth", "read:pets"),
+	)
+
+	return swag.New(
+		swag.Host("petstore.swagger.io"),
+		swag.BasePath("/v2"),
+		swag.Schemes("https", "http"),
+		swag.Endpoints(post, get),
+		swag.SecurityScheme("petstore_auth",
+			swagger.OAuth2Security("accessCode", "http://example.com/oauth/authorize", "http://example.com/oauth/token"),
+			swagger.OAuth2Scope("read:pets", "read your pets"),
+		),
+		swag.SecurityScheme("basic", swagger.BasicSecurity()),
+	)
+}
+
+func TestOpenAPI(t *testing.T) {
+	doc := petstore().OpenAPI()
+
+	assert.Equal(t, swagger.DefaultOpenAPIVersion, doc.OpenAPI)
+	assert.Equal(t, []swagger.Server{
+		{URL: "https://petstore.swagger.io/v2"},
+		{URL: "http://petstore.swagger.io/v2"},
+	}, doc.Servers)
+
+	post := doc.Paths["/pet"].Post
+	if assert.NotNil(t, post) && assert.NotNil(t, post.RequestBody) {
+		assert.Empty(t, post.Parameters)
+		assert.True(t, post.RequestBody.Required)
+		assert.Len(t, post.RequestBody.Content, 2)
+		assert.Equal(t, "#/components/schemas/swagger_testAnimal", post.RequestBody.Content["application/xml"].Schema.Ref)
+
+		response := post.Responses["200"]
+		assert.Equal(t, "#/components/schemas/swagger_testAnimal", response.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "integer", response.Headers["X-Rate-Limit"].Schema.Type)
+	}
+
+	get := doc.Paths["/pet/{petId}"].Get
+	if assert.NotNil(t, get) {
+		assert.Nil(t, get.RequestBody)
+		assert.Len(t, get.Parameters, 2)
+		assert.Equal(t, "path", get.Parameters[0].In)
+		assert.Equal(t, "integer", get.Parameters[0].Schema.Type)
+		assert.Len(t, get.Security.Requirements, 1)
+	}
+
+	schema := doc.Components.Schemas["swagger_testAnimal"]
+	if assert.NotNil(t, schema) {
+		assert.Equal(t, "object", schema.Type)
+		assert.Equal(t, "#/components/schemas/swagger_testCategory", schema.Properties["category"].Ref)
+		assert.Equal(t, "string", schema.Properties["tags"].Items.Type)
+	}
+	assert.Contains(t, doc.Components.Schemas, "swagger_testCategory")
+
+	oauth := doc.Components.SecuritySchemes["petstore_auth"]
+	assert.Equal(t, "oauth2", oauth.Type)
+	if assert.NotNil(t, oauth.Flows) && assert.NotNil(t, oauth.Flows.AuthorizationCode) {
+		assert.Equal(t, "http://example.com/oauth/token", oauth.Flows.AuthorizationCode.TokenURL)
+		assert.Equal(t, "read your pets", oauth.Flows.AuthorizationCode.Scopes["read:pets"])
+	}
+
+	basic := doc.Components.SecuritySchemes["basic"]
+	assert.Equal(t, "http", basic.Type)
+	assert.Equal(t, "basic", basic.Scheme)
+}
+
+func TestOpenAPIParameters(t *testing.T) {
+	upload := endpoint.New("post", "/pet/{petId}/image", "upload an image",
+		endpoint.Path("petId", "integer", "ID of pet", true),
+		endpoint.HeaderParam("X-Request-ID", "string", "request id", false),
+		endpoint.Cookie("session", "string", "session id", false),
+		endpoint.FormData("caption", "string", "image caption", false),
+		endpoint.File("image", "image to upload", true),
+	)
+	api := swag.New(swag.Endpoints(upload))
+
+	op := api.OpenAPI().Paths["/pet/{petId}/image"].Post
+	if assert.NotNil(t, op) && assert.NotNil(t, op.RequestBody) {
+		assert.Len(t, op.Parameters, 3)
+		assert.Equal(t, "header", op.Parameters[1].In)
+		assert.Equal(t, "cookie", op.Parameters[2].In)
+
+		assert.True(t, op.RequestBody.Required)
+		form := op.RequestBody.Content["multipart/form-data"].Schema
+		if assert.NotNil(t, form) {
+			assert.Equal(t, "object", form.Type)
+			assert.Equal(t, []string{"image"}, form.Required)
+			assert.Equal(t, &swagger.OpenAPISchema{Type: "string", Format: "binary", Description: "image to upload"}, form.Properties["image"])
+			assert.Equal(t, "string", form.Properties["caption"].Type)
+		}
+	}
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	upload := endpoint.New("post", "/pet/{petId}/image", "upload an image",
+		endpoint.ParameterRef("petId"),
+		endpoint.ParameterRef("image"),
+		endpoint.ResponseRef(http.StatusOK, "Pet"),
+	)
+	api := swag.New(
+		swag.Parameter("petId", swagger.Parameter{In: "path", Name: "petId", Type: "integer", Required: true}),
+		swag.Parameter("image", swagger.Parameter{In: "formData", Name: "image", Type: "file", Required: true}),
+		swag.Response("Pet", Animal{}, "a pet"),
+		swag.Endpoints(upload),
+	)
+
+	assert.Equal(t, []string{"multipart/form-data"}, api.Paths["/pet/{petId}/image"].Post.Consumes,
+		"expected references to file parameters to replace the default consumes")
+	assert.Equal(t, []string{"application/json"}, upload.Consumes, "expected the endpoint to be left unmodified")
+
+	doc := api.OpenAPI()
+	if assert.NotNil(t, doc.Components) {
+		assert.Equal(t, "integer", doc.Components.Parameters["petId"].Schema.Type)
+		assert.NotContains(t, doc.Components.Parameters, "image", "expected form parameters to be excluded")
+		assert.Equal(t, "#/components/schemas/swagger_testAnimal",
+			doc.Components.Responses["Pet"].Content["application/json"].Schema.Ref)
+	}
+
+	op := doc.Paths["/pet/{petId}/image"].Post
+	if assert.NotNil(t, op) {
+		assert.Equal(t, []swagger.OpenAPIParameter{{Ref: "#/components/parameters/petId"}}, op.Parameters)
+		assert.Equal(t, "#/components/responses/Pet", op.Responses["200"].Ref)
+		if assert.NotNil(t, op.RequestBody, "expected form parameters to be inlined") {
+			assert.Contains(t, op.RequestBody.Content["multipart/form-data"].Schema.Properties, "image")
+		}
+	}
+
+	data, err := json.Marshal(op.Responses["200"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"$ref": "#/components/responses/Pet"}`, string(data))
+}
+
+func TestOpenAPIRelativeServer(t *testing.T) {
+	doc := swag.New(swag.BasePath("/api/")).OpenAPI()
+	assert.Equal(t, []swagger.Server{{URL: "/api"}}, doc.Servers)
+	assert.NotNil(t, doc.Paths)
+	assert.Nil(t, doc.Components)
+}
+
+func TestHandlerOpenAPIVersion(t *testing.T) {
+	h := petstore().Handler(false, swagger.OpenAPIVersion("3.0.1"))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/swagger", nil)
+	w := httptest.NewRecorder()
+	h(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&doc))
+	assert.Equal(t, "3.0.1", doc["openapi"])
+	assert.NotContains(t, doc, "swagger")
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "http://localhost/v2"}}, doc["servers"])
+}
+
+func TestHandlerSwagger(t *testing.T) {
+	h := petstore().Handler(false)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/swagger", nil)
+	w := httptest.NewRecorder()
+	h(w, req)
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&doc))
+	assert.Equal(t, "2.0", doc["swagger"])
+	assert.Equal(t, "localhost", doc["host"])
+}
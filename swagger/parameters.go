@@ -0,0 +1,204 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// The following struct tags are read by MakeParameters to locate the parameter described by a field:
+//
+//	path:"petId"           a path parameter; path parameters are always required
+//	query:"limit"          a query parameter
+//	header:"X-Request-ID"  a header parameter
+//	cookie:"session"       a cookie parameter; cookie parameters are only described by OpenAPI 3.0
+//	form:"name"            a form parameter
+//
+// Options following the name e.g. query:"limit,omitempty" are ignored, and fields named "-" e.g. query:"-" are
+// skipped.  Parameters are documented using the same description, required, default, enum, format, and constraint tags
+// as definitions, and arrays may be delimited using the collectionFormat tag e.g. collectionFormat:"multi".  Untagged
+// fields are ignored, except for embedded structs whose fields are promoted
+var parameterTags = []struct {
+	tag string
+	in  string
+}{
+	{tag: "path", in: "path"},
+	{tag: "query", in: "query"},
+	{tag: "header", in: "header"},
+	{tag: "cookie", in: "cookie"},
+	{tag: "form", in: "formData"},
+}
+
+const tagCollectionFormat = "collectionFormat"
+
+// parameterLocation returns the location and name of the parameter described by a field; in is empty if the field
+// does not describe a parameter, and tagged is true if the field is tagged with a location, even if it is skipped
+func parameterLocation(f reflect.StructField) (in, name string, tagged bool) {
+	for _, location := range parameterTags {
+		v, ok := f.Tag.Lookup(location.tag)
+		if !ok {
+			continue
+		}
+
+		name = strings.TrimSpace(strings.Split(v, ",")[0])
+		if name == "-" {
+			return "", "", true
+		}
+		if name == "" {
+			name = f.Name
+		}
+		return location.in, name, true
+	}
+
+	return "", "", false
+}
+
+// isSimple returns true if a non-body parameter, or the items of an array parameter, may be of the swagger type, typ
+func isSimple(typ string, items *Items) bool {
+	switch typ {
+	case "string", "number", "integer", "boolean":
+		return true
+	case "array":
+		return items != nil && items.Ref == "" && isSimple(items.Type, items.Items)
+	}
+	return false
+}
+
+// parameterProperty describes the go type, t, of the parameter, name, located in in
+func (r *reflector) parameterProperty(t reflect.Type, in, name string) (Property, error) {
+	p := r.inspect(t, "")
+	r.refs = nil // parameters never reference definitions
+
+	if p.Ref != "" || !isSimple(p.Type, p.Items) {
+		return Property{}, fmt.Errorf("%v parameter, %v, has unsupported type, %v", in, name, t)
+	}
+	return p, nil
+}
+
+// makeParameter describes the field, f, as a parameter located in in
+func (r *reflector) makeParameter(f reflect.StructField, in, name string) (Parameter, error) {
+	p, err := r.parameterProperty(f.Type, in, name)
+	if err != nil {
+		return Parameter{}, err
+	}
+
+	applyTags(&p, f.Tag)
+	applyConstraints(&p, f.Tag)
+
+	param := Parameter{
+		In:          in,
+		Name:        name,
+		Description: p.Description,
+		Required:    in == "path" || r.isRequired(field{field: f}),
+		Type:        p.Type,
+		Format:      p.Format,
+		Items:       p.Items,
+		Default:     p.Default,
+		Enum:        p.Enum,
+		Constraints: p.Constraints,
+	}
+	if p.Type == "array" {
+		param.CollectionFormat = f.Tag.Get(tagCollectionFormat)
+	}
+
+	return param, nil
+}
+
+// parameters describes the tagged fields of the struct, t, and of the structs it embeds
+func (r *reflector) parameters(t reflect.Type, visited map[reflect.Type]bool) ([]Parameter, Errors) {
+	var params []Parameter
+	var errs Errors
+
+	if visited[t] {
+		return nil, nil
+	}
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		in, name, tagged := parameterLocation(f)
+		if in == "" {
+			if embedded := indirect(f.Type); !tagged && f.Anonymous && embedded.Kind() == reflect.Struct {
+				promoted, promotedErrs := r.parameters(embedded, visited)
+				params = append(params, promoted...)
+				errs = append(errs, promotedErrs...)
+			}
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		param, err := r.makeParameter(f, in, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		params = append(params, param)
+	}
+
+	return params, errs
+}
+
+// MakeParameters takes a struct or pointer to a struct and returns a Parameter for each field tagged with the location
+// of the parameter e.g. `query:"limit"`.  The type, format, and items of each parameter are derived from the go type
+// of the field
+func MakeParameters(prototype interface{}) ([]Parameter, error) {
+	t := typeOf(prototype)
+	if t == nil {
+		return nil, fmt.Errorf("parameters prototype must be a struct")
+	}
+
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parameters prototype, %v, must be a struct", t)
+	}
+
+	params, errs := (&reflector{}).parameters(t, map[reflect.Type]bool{})
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// MakeParameter returns the parameter, name, located in in e.g. query, whose type, format, and items are derived from
+// the go type of prototype; prototype may also be a reflect.Type.  Go types that non-body parameters are unable to
+// describe, e.g. structs and maps, are rejected
+func MakeParameter(in, name string, prototype interface{}) (Parameter, error) {
+	t := typeOf(prototype)
+	if t == nil {
+		return Parameter{}, fmt.Errorf("%v parameter, %v, has no type", in, name)
+	}
+
+	p, err := (&reflector{}).parameterProperty(t, in, name)
+	if err != nil {
+		return Parameter{}, err
+	}
+
+	return Parameter{
+		In:          in,
+		Name:        name,
+		Required:    in == "path",
+		Type:        p.Type,
+		Format:      p.Format,
+		Items:       p.Items,
+		Enum:        p.Enum,
+		Constraints: p.Constraints,
+	}, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/savaki/swag"
+	"github.com/savaki/swag/endpoint"
+	"github.com/savaki/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Paging struct {
+	Limit  int    `query:"limit" description:"maximum number of results" default:"20"`
+	Cursor string `query:"cursor,omitempty"`
+}
+
+type ListPetsParams struct {
+	Paging
+	OwnerID   int64     `path:"ownerId"`
+	Status    []string  `query:"status" enum:"available,pending,sold" collectionFormat:"multi"`
+	Since     time.Time `query:"since"`
+	RequestID string    `header:"X-Request-ID" required:"true"`
+	Session   string    `cookie:"session"`
+	Name      *string   `form:"name"`
+	Ignored   string
+	Skipped   string `query:"-"`
+	hidden    string `query:"hidden"`
+}
+
+func TestMakeParameters(t *testing.T) {
+	params, err := swagger.MakeParameters(&ListPetsParams{})
+	assert.Nil(t, err)
+	assert.Equal(t, []swagger.Parameter{
+		{In: "query", Name: "limit", Description: "maximum number of results", Type: "integer", Format: "int32", Default: int64(20)},
+		{In: "query", Name: "cursor", Type: "string"},
+		{In: "path", Name: "ownerId", Required: true, Type: "integer", Format: "int64"},
+		{
+			In:               "query",
+			Name:             "status",
+			Type:             "array",
+			Items:            &swagger.Items{Type: "string", Enum: []interface{}{"available", "pending", "sold"}},
+			CollectionFormat: "multi",
+		},
+		{In: "query", Name: "since", Type: "string", Format: "date-time"},
+		{In: "header", Name: "X-Request-ID", Required: true, Type: "string"},
+		{In: "cookie", Name: "session", Type: "string"},
+		{In: "formData", Name: "name", Type: "string"},
+	}, params)
+
+	_, err = swagger.MakeParameters(reflect.TypeOf(ListPetsParams{}))
+	assert.Nil(t, err, "expected reflect.Type to be accepted")
+}
+
+func TestMakeParametersErrors(t *testing.T) {
+	type Invalid struct {
+		Owner  Category          `query:"owner"`
+		Labels map[string]string `header:"X-Labels"`
+		Valid  string            `query:"valid"`
+	}
+
+	params, err := swagger.MakeParameters(Invalid{})
+	assert.Nil(t, params)
+	if errs, ok := err.(swagger.Errors); assert.True(t, ok, "expected every error to be reported") {
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], "query parameter, owner, has unsupported type, swagger_test.Category")
+		assert.EqualError(t, errs[1], "header parameter, X-Labels, has unsupported type, map[string]string")
+	}
+
+	_, err = swagger.MakeParameters(42)
+	assert.EqualError(t, err, "parameters prototype, int, must be a struct")
+
+	_, err = swagger.MakeParameters(nil)
+	assert.EqualError(t, err, "parameters prototype must be a struct")
+}
+
+func TestMakeParameter(t *testing.T) {
+	p, err := swagger.MakeParameter("path", "id", reflect.TypeOf(uint64(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, swagger.Parameter{In: "path", Name: "id", Required: true, Type: "integer", Format: "int64"}, p)
+
+	p, err = swagger.MakeParameter("query", "grid", [][]float32{})
+	assert.Nil(t, err)
+	assert.Equal(t, &swagger.Items{Type: "array", Items: &swagger.Items{Type: "number", Format: "float"}}, p.Items)
+
+	_, err = swagger.MakeParameter("query", "labels", map[string]string{})
+	assert.EqualError(t, err, "query parameter, labels, has unsupported type, map[string]string")
+
+	_, err = swagger.MakeParameter("query", "any", nil)
+	assert.EqualError(t, err, "query parameter, any, has no type")
+}
+
+func TestOpenAPIParameterStyle(t *testing.T) {
+	type Params struct {
+		IDs    []int64  `path:"ids"`
+		Tags   []string `query:"tags"`
+		Status []string `query:"status" collectionFormat:"multi"`
+	}
+
+	e := endpoint.New("get", "/pets/{ids}", "find pets", endpoint.Parameters(Params{}))
+	params := swag.New(swag.Endpoints(e)).OpenAPI().Paths["/pets/{ids}"].Get.Parameters
+	if assert.Len(t, params, 3) {
+		assert.Equal(t, "", params[0].Style)
+		assert.Nil(t, params[0].Explode)
+		assert.Equal(t, "integer", params[0].Schema.Items.Type)
+
+		assert.Equal(t, "form", params[1].Style)
+		assert.False(t, *params[1].Explode, "expected csv to be preserved")
+
+		assert.Equal(t, "form", params[2].Style)
+		assert.True(t, *params[2].Explode)
+	}
+}
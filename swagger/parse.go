@@ -0,0 +1,125 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// UnmarshalJSON decodes a swagger 2.0 document; the path and method of each endpoint are restored from its location
+// within paths, and endpoints without tags are given an empty list so as to remain valid once encoded
+func (a *API) UnmarshalJSON(data []byte) error {
+	type document API // prevents recursion into UnmarshalJSON
+	if err := json.Unmarshal(data, (*document)(a)); err != nil {
+		return err
+	}
+
+	for path, endpoints := range a.Paths {
+		methods := map[string]*Endpoint{
+			"DELETE":  endpoints.Delete,
+			"HEAD":    endpoints.Head,
+			"GET":     endpoints.Get,
+			"OPTIONS": endpoints.Options,
+			"POST":    endpoints.Post,
+			"PUT":     endpoints.Put,
+			"PATCH":   endpoints.Patch,
+			"TRACE":   endpoints.Trace,
+			"CONNECT": endpoints.Connect,
+		}
+		for method, e := range methods {
+			if e != nil {
+				e.Path = path
+				e.Method = method
+				if e.Tags == nil {
+					e.Tags = []string{}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes a list of security requirements; an empty list disables security
+func (s *SecurityRequirement) UnmarshalJSON(data []byte) error {
+	var requirements []map[string][]string
+	if err := json.Unmarshal(data, &requirements); err != nil {
+		return err
+	}
+
+	s.Requirements = nil
+	s.DisableSecurity = len(requirements) == 0
+	if !s.DisableSecurity {
+		s.Requirements = requirements
+	}
+
+	return nil
+}
+
+// ParseJSON decodes the swagger 2.0 json document, data.  Documents limited to what API models encode back to the
+// same json, except that host, tags, info.contact, info.license, the externalDocs of tags, the required field of
+// parameters, and the type, format, and description of headers are always present.  Anything else is dropped e.g. top-level consumes, produces,
+// and externalDocs, schema titles and xml, response examples, and vendor extensions other than x-nullable and
+// x-discriminator-value.  Markers that only the OpenAPI 3.0 document carries, e.g. writeOnly and discriminator
+// mappings, are absent from swagger 2.0 documents and so are not restored
+func ParseJSON(data []byte) (*API, error) {
+	api := &API{}
+	if err := json.Unmarshal(data, api); err != nil {
+		return nil, err
+	}
+	return api, nil
+}
+
+// ParseYAML decodes the swagger 2.0 yaml document, data, retaining what ParseJSON does.  As yaml is a superset of json,
+// json documents are also accepted
+func ParseYAML(data []byte) (*API, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJSON(data)
+}
+
+// jsonValue converts the decoded yaml value, v, into a value encoding/json is able to encode; yaml permits mappings
+// with non-string keys e.g. response codes
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = jsonValue(item)
+		}
+		return m
+
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = jsonValue(item)
+		}
+		return items
+
+	default:
+		return value
+	}
+}
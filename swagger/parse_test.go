@@ -0,0 +1,137 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/savaki/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSON(t *testing.T) {
+	expected, err := json.Marshal(petstore())
+	assert.Nil(t, err)
+
+	api, err := swagger.ParseJSON(expected)
+	assert.Nil(t, err)
+
+	actual, err := json.Marshal(api)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+
+	get := api.Paths["/pet/{petId}"].Get
+	if assert.NotNil(t, get) {
+		assert.Equal(t, "/pet/{petId}", get.Path)
+		assert.Equal(t, "GET", get.Method)
+		assert.Equal(t, []map[string][]string{{"petstore_auth": {"read:pets"}}}, get.Security.Requirements)
+	}
+	assert.Equal(t, "POST", api.Paths["/pet"].Post.Method)
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	expected, err := ioutil.ReadFile("testdata/roundtrip.json")
+	assert.Nil(t, err)
+
+	api, err := swagger.ParseJSON(expected)
+	assert.Nil(t, err)
+
+	actual, err := json.Marshal(api)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+
+	assert.Nil(t, api.Validate(), "expected the path parameter to be inherited")
+
+	get := api.OpenAPI().Paths["/pets/{petId}"].Get
+	assert.True(t, get.Deprecated)
+	if assert.Len(t, get.Parameters, 1) {
+		assert.Equal(t, "petId", get.Parameters[0].Name, "expected the path parameter to be inherited")
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/petstore.yaml")
+	assert.Nil(t, err)
+
+	api, err := swagger.ParseYAML(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "2.0", api.Swagger)
+	assert.Equal(t, "Swagger Petstore", api.Info.Title)
+	assert.Equal(t, "/v2", api.BasePath)
+	assert.Equal(t, "header", api.SecurityDefinitions["api_key"].In)
+
+	get := api.Paths["/pet/{petId}"].Get
+	if assert.NotNil(t, get) {
+		assert.Equal(t, "GET", get.Method)
+		assert.Equal(t, "/pet/{petId}", get.Path)
+		assert.Equal(t, "integer", get.Parameters[0].Type)
+		assert.Equal(t, "#/definitions/Pet", get.Responses["200"].Schema.Ref)
+		assert.Equal(t, "Pet not found", get.Responses["404"].Description)
+		assert.False(t, get.Security.DisableSecurity)
+	}
+
+	del := api.Paths["/pet/{petId}"].Delete
+	if assert.NotNil(t, del) {
+		assert.Equal(t, "DELETE", del.Method)
+		assert.True(t, del.Security.DisableSecurity, "expected an empty list to disable security")
+	}
+
+	pet := api.Definitions["Pet"]
+	assert.Equal(t, []string{"name"}, pet.Required)
+	assert.Equal(t, "doggie", pet.Properties["name"].Example)
+	assert.Equal(t, "string", pet.Properties["tags"].Items.Type)
+
+	_, err = swagger.ParseYAML([]byte("swagger: [unterminated"))
+	assert.NotNil(t, err)
+}
+
+func TestParseExtend(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/petstore.yaml")
+	assert.Nil(t, err)
+
+	api, err := swagger.ParseYAML(data)
+	assert.Nil(t, err)
+
+	api.AddEndpoint(petstore().Paths["/pet"].Post)
+	assert.NotNil(t, api.Paths["/pet"].Post)
+	assert.Contains(t, api.Definitions, "Pet", "expected parsed definitions to be retained")
+	assert.Contains(t, api.Definitions, "swagger_testAnimal")
+}
+
+func TestParseNameCollision(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/petstore.yaml")
+	assert.Nil(t, err)
+
+	api, err := swagger.ParseYAML(data)
+	assert.Nil(t, err)
+	api.SchemaOptions.Naming = swagger.ShortNames
+
+	type Pet struct {
+		Nickname string `json:"nickname"`
+	}
+	api.AddEndpoint(&swagger.Endpoint{
+		Method:    "GET",
+		Path:      "/pets/mine",
+		Responses: map[string]swagger.Response{"200": {Description: "ok", Schema: &swagger.Schema{Prototype: Pet{}}}},
+	})
+
+	ref := api.Paths["/pets/mine"].Get.Responses["200"].Schema.Ref
+	assert.Equal(t, "#/definitions/github.com.savaki.swag.swagger_test.Pet", ref,
+		"expected parsed definitions to keep their names")
+	assert.NotContains(t, api.Definitions["Pet"].Properties, "nickname")
+	assert.Contains(t, api.Definitions["github.com.savaki.swag.swagger_test.Pet"].Properties, "nickname")
+}
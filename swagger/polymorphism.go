@@ -0,0 +1,190 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Discriminator identifies the property whose value determines the concrete type of a polymorphic definition
+type Discriminator struct {
+	// PropertyName is the name of the discriminator property; it is always required
+	PropertyName string
+
+	// Mapping maps values of the discriminator property onto the go types they identify.  Each type implicitly extends
+	// the base type unless it explicitly extends another
+	Mapping map[string]reflect.Type
+}
+
+// Extend declares that the go type of child extends the go type of parent.  Either may be a prototype or a
+// reflect.Type
+func (o *SchemaOptions) Extend(child, parent interface{}) {
+	if o.Extends == nil {
+		o.Extends = map[reflect.Type]reflect.Type{}
+	}
+	o.Extends[indirect(typeOf(child))] = indirect(typeOf(parent))
+}
+
+// Discriminate declares that the go type of base is polymorphic; the value of property, propertyName, identifies
+// the concrete type using mapping, a map of values to prototypes or reflect.Types
+func (o *SchemaOptions) Discriminate(base interface{}, propertyName string, mapping map[string]interface{}) {
+	if o.Discriminators == nil {
+		o.Discriminators = map[reflect.Type]Discriminator{}
+	}
+
+	d := Discriminator{
+		PropertyName: propertyName,
+		Mapping:      map[string]reflect.Type{},
+	}
+	for value, prototype := range mapping {
+		d.Mapping[value] = indirect(typeOf(prototype))
+	}
+
+	o.Discriminators[indirect(typeOf(base))] = d
+}
+
+// byString sorts types by their string representation
+type byString []reflect.Type
+
+func (x byString) Len() int           { return len(x) }
+func (x byString) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
+func (x byString) Less(i, j int) bool { return x[i].String() < x[j].String() }
+
+// parent returns the go type that t extends either explicitly or by being mapped by the discriminator of a base type
+func (r *reflector) parent(t reflect.Type) (reflect.Type, bool) {
+	if parent, ok := r.Extends[t]; ok {
+		return parent, true
+	}
+
+	bases := make([]reflect.Type, 0, len(r.Discriminators))
+	for base := range r.Discriminators {
+		bases = append(bases, base)
+	}
+	sort.Sort(byString(bases))
+
+	for _, base := range bases {
+		for _, mapped := range r.Discriminators[base].Mapping {
+			if mapped == t && base != t {
+				return base, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// discriminatorValue returns the value of the discriminator property that identifies t, if any
+func (r *reflector) discriminatorValue(t reflect.Type) (string, bool) {
+	bases := make([]reflect.Type, 0, len(r.Discriminators))
+	for base := range r.Discriminators {
+		bases = append(bases, base)
+	}
+	sort.Sort(byString(bases))
+
+	for _, base := range bases {
+		mapping := r.Discriminators[base].Mapping
+
+		values := make([]string, 0, len(mapping))
+		for value := range mapping {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		for _, value := range values {
+			if mapping[value] == t {
+				return value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// inherited returns the names of the properties t has inherited from parent and its ancestors
+func (r *reflector) inherited(parent reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	seen := map[reflect.Type]bool{}
+
+	for t, ok := parent, true; ok && !seen[t]; t, ok = r.parent(t) {
+		seen[t] = true
+		for _, f := range fields(t) {
+			names[f.name] = true
+		}
+	}
+
+	return names
+}
+
+// extend rewrites obj as the allOf its parent and the properties obj does not inherit.  Go types that extend
+// another typically embed it, so inherited properties are recognized by name
+func (r *reflector) extend(obj *Object, parent reflect.Type) {
+	inherited := r.inherited(parent)
+
+	own := Object{
+		Type:       "object",
+		Properties: map[string]Property{},
+	}
+	for name, p := range obj.Properties {
+		if !inherited[name] {
+			own.Properties[name] = p
+		}
+	}
+	for _, name := range obj.Required {
+		if !inherited[name] {
+			own.Required = append(own.Required, name)
+		}
+	}
+
+	r.refs = append(r.refs, parent)
+
+	obj.Type = ""
+	obj.Required = nil
+	obj.Properties = nil
+	obj.AllOf = []Object{
+		{Ref: makeRef(r.name(parent))},
+		own,
+	}
+}
+
+// discriminate adds the discriminator, d, to the base definition, obj
+func (r *reflector) discriminate(obj *Object, d Discriminator) {
+	obj.Discriminator = d.PropertyName
+
+	if _, ok := obj.Properties[d.PropertyName]; !ok {
+		obj.Properties[d.PropertyName] = Property{Type: "string"}
+	}
+
+	required := false
+	for _, name := range obj.Required {
+		required = required || name == d.PropertyName
+	}
+	if !required {
+		obj.Required = append(obj.Required, d.PropertyName)
+	}
+
+	values := make([]string, 0, len(d.Mapping))
+	for value := range d.Mapping {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	obj.DiscriminatorMapping = map[string]string{}
+	for _, value := range values {
+		t := d.Mapping[value]
+		obj.DiscriminatorMapping[value] = r.name(t)
+		r.refs = append(r.refs, t)
+	}
+}
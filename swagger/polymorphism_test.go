@@ -0,0 +1,108 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Event struct {
+	ID   string `json:"id" required:"true"`
+	Kind string `json:"kind"`
+}
+
+type Created struct {
+	Event
+	Name string `json:"name" required:"true"`
+}
+
+type Deleted struct {
+	Event
+	Reason string `json:"reason"`
+}
+
+type Restored struct {
+	Deleted
+	By string `json:"by"`
+}
+
+func polymorphic() *reflector {
+	r := &reflector{}
+	r.Discriminate(Event{}, "kind", map[string]interface{}{
+		"created": Created{},
+		"deleted": &Deleted{},
+	})
+	r.Extend(Restored{}, Deleted{})
+	return r
+}
+
+func TestDiscriminator(t *testing.T) {
+	v := polymorphic().define(Event{})
+	assert.Len(t, v, 3, "expected mapped types to be defined")
+
+	event := v["swaggerEvent"]
+	assert.Equal(t, "kind", event.Discriminator)
+	assert.Equal(t, []string{"id", "kind"}, event.Required, "expected the discriminator to be required")
+	assert.Equal(t, map[string]string{"created": "swaggerCreated", "deleted": "swaggerDeleted"}, event.DiscriminatorMapping)
+
+	data, err := json.Marshal(v["swaggerCreated"])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"allOf": [
+			{"$ref": "#/definitions/swaggerEvent"},
+			{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+		],
+		"x-discriminator-value": "created"
+	}`, string(data), "expected subtypes to identify their discriminator value")
+	assert.Equal(t, "", v["swaggerEvent"].DiscriminatorValue)
+}
+
+func TestExtends(t *testing.T) {
+	v := polymorphic().define(Restored{})
+	assert.Len(t, v, 4, "expected ancestors, and the types they map, to be defined")
+
+	restored := v["swaggerRestored"]
+	assert.Equal(t, "#/definitions/swaggerDeleted", restored.AllOf[0].Ref)
+	assert.Len(t, restored.AllOf[1].Properties, 1, "expected properties of every ancestor to be inherited")
+	assert.Contains(t, restored.AllOf[1].Properties, "by")
+
+	deleted := v["swaggerDeleted"]
+	assert.Equal(t, "#/definitions/swaggerEvent", deleted.AllOf[0].Ref, "expected mapped types to extend the base")
+}
+
+func TestDiscriminatorMissingProperty(t *testing.T) {
+	r := &reflector{}
+	r.Discriminate(Cell{}, "type", nil)
+
+	obj := r.defineObject(Cell{})
+	assert.Equal(t, "string", obj.Properties["type"].Type)
+	assert.Equal(t, []string{"type"}, obj.Required)
+}
+
+func TestOpenAPIDiscriminator(t *testing.T) {
+	api := &API{
+		SchemaOptions: polymorphic().SchemaOptions,
+		Definitions:   polymorphic().define(Event{}),
+	}
+
+	schemas := api.OpenAPI().Components.Schemas
+	event := schemas["swaggerEvent"]
+	assert.Equal(t, "kind", event.Discriminator.PropertyName)
+	assert.Equal(t, "#/components/schemas/swaggerCreated", event.Discriminator.Mapping["created"])
+	assert.Equal(t, "#/components/schemas/swaggerEvent", schemas["swaggerCreated"].AllOf[0].Ref)
+}
@@ -19,7 +19,50 @@ import (
 	"strings"
 )
 
-func inspect(t reflect.Type, jsonTag string) Property {
+// isMapKey returns true if encoding/json is able to use the type as the key of a json object
+func isMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return t.Implements(textMarshalerType)
+}
+
+// SchemaOptions customizes how go types are converted into swagger definitions
+type SchemaOptions struct {
+	// InferRequired marks fields tagged validate:"required", and non-pointer fields without omitempty, as required.
+	// Individual fields may opt out via the required:"false" tag
+	InferRequired bool
+
+	// InferNullable marks pointer fields as nullable.  Individual fields may opt out via the nullable:"false" tag
+	InferNullable bool
+
+	// Comments, when set, provides the descriptions of definitions and their properties.  Description tags take
+	// precedence
+	Comments *Comments
+
+	// Naming determines the names of definitions; defaults to PackageNames
+	Naming NamingStrategy
+
+	// Extends maps go types onto the go type they extend; extended types are described using allOf
+	Extends map[reflect.Type]reflect.Type
+
+	// Discriminators maps polymorphic base types onto the discriminator that identifies their concrete type
+	Discriminators map[reflect.Type]Discriminator
+}
+
+// reflector converts go types into swagger definitions
+type reflector struct {
+	SchemaOptions
+
+	names map[reflect.Type]string // definition name by go type
+	types map[string]reflect.Type // go type by definition name
+	refs  []reflect.Type          // types referenced by inspect that may require a definition
+}
+
+func (r *reflector) inspect(t reflect.Type, jsonTag string) Property {
 	p := Property{
 		GoType: t,
 	}
@@ -29,6 +72,11 @@ func inspect(t reflect.Type, jsonTag string) Property {
 		return p
 	}
 
+	if v, ok := wellKnown(t); ok {
+		v.GoType = t
+		return v
+	}
+
 	switch p.GoType.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
 		p.Type = "integer"
@@ -53,168 +101,326 @@ func inspect(t reflect.Type, jsonTag string) Property {
 		p.Type = "string"
 
 	case reflect.Struct:
-		name := makeName(p.GoType)
-		p.Ref = makeRef(name)
+		// anonymous structs have no name to define them by and so are described inline
+		if t.Name() == "" {
+			p.Type = "object"
+			p.Properties, p.Required = r.properties(t)
+			break
+		}
+
+		p.Ref = makeRef(r.name(p.GoType))
+		r.refs = append(r.refs, p.GoType)
 
 	case reflect.Ptr:
-		p.GoType = t.Elem()
-		name := makeName(p.GoType)
-		p.Ref = makeRef(name)
+		return r.inspect(t.Elem(), jsonTag)
 
-	case reflect.Slice:
-		p.Type = "array"
-		p.Items = &Items{}
+	case reflect.Map:
+		p.Type = "object"
 
-		p.GoType = t.Elem() // dereference the slice
-		switch p.GoType.Kind() {
-		case reflect.Ptr:
-			p.GoType = p.GoType.Elem()
-			name := makeName(p.GoType)
-			p.Items.Ref = makeRef(name)
+		// json objects may only be keyed by strings; map keys that encoding/json cannot convert are left free-form
+		if isMapKey(t.Key()) {
+			elem := r.inspect(t.Elem(), "")
+			p.GoType = elem.GoType // dereference the map
+			p.AdditionalProperties = &elem
+		}
 
-		case reflect.Struct:
-			name := makeName(p.GoType)
-			p.Items.Ref = makeRef(name)
+	case reflect.Slice, reflect.Array:
+		// encoding/json encodes byte slices, but not byte arrays, as base64 strings
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			p.Type = "string"
+			p.Format = "byte"
+			break
+		}
 
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-			p.Items.Type = "integer"
-			p.Items.Format = "int32"
+		p.Type = "array"
 
-		case reflect.Int64, reflect.Uint64:
-			p.Items.Type = "integer"
-			p.Items.Format = "int64"
+		elem := r.inspect(t.Elem(), "")
+		p.GoType = elem.GoType // dereference the slice
+		p.Items = makeItems(elem)
 
-		case reflect.Float64:
-			p.Items.Type = "number"
-			p.Items.Format = "double"
+		// fixed size arrays always contain exactly len elements
+		if t.Kind() == reflect.Array {
+			minItems, maxItems := int64(t.Len()), int64(t.Len())
+			p.MinItems, p.MaxItems = &minItems, &maxItems
+		}
+	}
 
-		case reflect.Float32:
-			p.Items.Type = "number"
-			p.Items.Format = "float"
+	return p
+}
 
-		case reflect.String:
-			p.Items.Type = "string"
-		}
+// makeItems describes the elements of an array using the property of the element type
+func makeItems(p Property) *Items {
+	return &Items{
+		Type:                 p.Type,
+		Format:               p.Format,
+		Ref:                  p.Ref,
+		Enum:                 p.Enum,
+		Constraints:          p.Constraints,
+		Items:                p.Items,
+		AdditionalProperties: p.AdditionalProperties,
+		Required:             p.Required,
+		Properties:           p.Properties,
 	}
+}
 
+// wrapRef moves the reference of p into allOf if p is also nullable, read only, or write only; the spec ignores the
+// siblings of $ref, so the markers would otherwise have no effect
+func wrapRef(p Property) Property {
+	if p.Ref != "" && (p.Nullable || p.ReadOnly || p.WriteOnly) {
+		p.AllOf = []Property{{Ref: p.Ref}}
+		p.Ref = ""
+	}
 	return p
 }
 
-func defineObject(v interface{}) Object {
-	var required []string
+// isRequired determines whether the field should be listed in the required properties of its definition.  An explicit
+// required tag takes precedence over inference, which considers validate:"required" before the type and json tag
+func (r *reflector) isRequired(f field) bool {
+	switch f.field.Tag.Get("required") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
 
-	var t reflect.Type
-	switch value := v.(type) {
-	case reflect.Type:
-		t = value
-	default:
-		t = reflect.TypeOf(v)
+	if !r.InferRequired {
+		return false
 	}
 
-	properties := map[string]Property{}
-	isArray := t.Kind() == reflect.Slice
+	for _, rule := range strings.Split(f.field.Tag.Get(tagValidate), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
 
-	if isArray {
-		t = t.Elem()
+	switch f.field.Type.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return false
 	}
-	if t.Kind() == reflect.Ptr {
+
+	options := strings.Split(f.field.Tag.Get("json"), ",")[1:]
+	for _, option := range options {
+		if strings.TrimSpace(option) == "omitempty" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typeOf returns the go type of the prototype, v, which may itself be a reflect.Type
+func typeOf(v interface{}) reflect.Type {
+	if t, ok := v.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(v)
+}
+
+// indirect returns the type t points to, however many pointers deep
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
+	return t
+}
 
-	if t.Kind() != reflect.Struct {
-		p := inspect(t, "")
-		return Object{
-			IsArray:  isArray,
-			GoType:   t,
-			Type:     p.Type,
-			Format:   p.Format,
-			Name:     t.Kind().String(),
-			Required: required,
+// isInline returns true if the prototype type, t, has no definition of its own and is instead described inline by
+// makeSchema; this is the case for anything other than objects and arrays of objects e.g. string, time.Time,
+// []string, [][]int, and map[string]int
+func isInline(t reflect.Type) bool {
+	t = indirect(t)
+
+	switch t.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		if _, ok := wellKnown(t); ok {
+			return true
 		}
+		return !isObject(indirect(t.Elem()))
 	}
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
+	return !isObject(t)
+}
 
-		// skip unexported fields
-		if strings.ToLower(field.Name[0:1]) == field.Name[0:1] {
-			continue
+// isNested returns true for maps, arrays of arrays, and arrays of maps; define describes every other prototype with
+// a definition of its own, even where makeSchema describes it inline e.g. []string
+func isNested(t reflect.Type) bool {
+	t = indirect(t)
+
+	switch t.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		switch indirect(t.Elem()).Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array:
+			return true
 		}
+	}
 
-		// determine the json name of the field
-		name := strings.TrimSpace(field.Tag.Get("json"))
-		if name == "" || strings.HasPrefix(name, ",") {
-			name = field.Name
+	return false
+}
 
-		} else {
-			// strip out things like , omitempty
-			parts := strings.Split(name, ",")
-			name = parts[0]
-		}
+// defineObject describes v using the default schema options
+func defineObject(v interface{}) Object {
+	return (&reflector{}).defineObject(v)
+}
 
-		parts := strings.Split(name, ",") // foo,omitempty => foo
-		name = parts[0]
-		if name == "-" {
-			// honor json ignore tag
-			continue
-		}
+// define describes v and every type it references using the default schema options
+func define(v interface{}) map[string]Object {
+	return (&reflector{}).define(v)
+}
+
+// properties describes the fields of the struct, t, and returns the names of those that are required
+func (r *reflector) properties(t reflect.Type) (map[string]Property, []string) {
+	var required []string
+
+	properties := map[string]Property{}
+	for _, f := range fields(t) {
+		name := f.name
 
 		// determine if this field is required or not
-		if v := field.Tag.Get("required"); v == "true" {
+		if r.isRequired(f) {
 			if required == nil {
 				required = []string{}
 			}
 			required = append(required, name)
 		}
 
-		p := inspect(field.Type, field.Tag.Get("json"))
-		properties[name] = p
+		p := r.inspect(f.field.Type, f.field.Tag.Get("json"))
+		if r.InferNullable && f.field.Type.Kind() == reflect.Ptr {
+			p.Nullable = true
+		}
+		if text := r.Comments.fieldDoc(t, f); text != "" {
+			p.Description = text
+		}
+		applyTags(&p, f.field.Tag)
+		applyConstraints(&p, f.field.Tag)
+		properties[name] = wrapRef(p)
 	}
 
-	return Object{
-		IsArray:    isArray,
-		GoType:     t,
-		Type:       "object",
-		Name:       makeName(t),
-		Required:   required,
-		Properties: properties,
+	return properties, required
+}
+
+func (r *reflector) defineObject(v interface{}) Object {
+	t := indirect(typeOf(v))
+
+	isArray := t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+
+	if isArray {
+		t = indirect(t.Elem())
 	}
+
+	if t.Kind() == reflect.Map {
+		p := r.inspect(t, "")
+		return Object{
+			IsArray:              isArray,
+			GoType:               p.GoType,
+			Type:                 p.Type,
+			Name:                 t.Kind().String(),
+			AdditionalProperties: p.AdditionalProperties,
+		}
+	}
+
+	if !isObject(t) {
+		name := t.Kind().String()
+		if _, ok := wellKnown(t); ok {
+			name = r.name(t)
+		}
+
+		p := r.inspect(t, "")
+		return Object{
+			IsArray:    isArray,
+			GoType:     t,
+			Type:       p.Type,
+			Format:     p.Format,
+			Name:       name,
+			Required:   p.Required,
+			Properties: p.Properties,
+		}
+	}
+
+	properties, required := r.properties(t)
+
+	obj := Object{
+		IsArray:     isArray,
+		GoType:      t,
+		Type:        "object",
+		Name:        r.name(t),
+		Description: r.Comments.typeDoc(t),
+		Required:    required,
+		Properties:  properties,
+	}
+
+	if d, ok := r.Discriminators[t]; ok {
+		r.discriminate(&obj, d)
+	}
+	if parent, ok := r.parent(t); ok {
+		r.extend(&obj, parent)
+	}
+	if value, ok := r.discriminatorValue(t); ok {
+		obj.DiscriminatorValue = value
+	}
+
+	return obj
 }
 
-func define(v interface{}) map[string]Object {
+// define describes v and every type it references, however deeply nested within pointers, slices, arrays, and maps.
+// Each type is defined once, so self-referencing and mutually recursive types are supported
+func (r *reflector) define(v interface{}) map[string]Object {
+	return r.definitions(v, !isNested(typeOf(v)))
+}
+
+// defineSchema describes the types referenced by the schema makeSchema returns for the prototype.  Unlike define, the
+// prototype has no definition of its own when the schema describes it inline
+func (r *reflector) defineSchema(prototype interface{}) map[string]Object {
+	return r.definitions(prototype, !isInline(typeOf(prototype)))
+}
+
+// definitions describes the types v references and, if root is true, v itself
+func (r *reflector) definitions(v interface{}, root bool) map[string]Object {
 	objMap := map[string]Object{}
 
-	obj := defineObject(v)
-	objMap[obj.Name] = obj
-
-	dirty := true
-
-	for dirty {
-		dirty = false
-		for _, d := range objMap {
-			for _, p := range d.Properties {
-				if p.GoType.Kind() == reflect.Struct {
-					name := makeName(p.GoType)
-					if _, exists := objMap[name]; !exists {
-						child := defineObject(p.GoType)
-						objMap[child.Name] = child
-						dirty = true
-					}
-				}
-			}
+	r.refs = nil
+	obj := r.defineObject(v)
+	if root {
+		objMap[obj.Name] = obj
+	}
+
+	// refs are appended in the stable order of fields so names are assigned deterministically
+	for len(r.refs) > 0 {
+		t := r.refs[0]
+		r.refs = r.refs[1:]
+
+		if _, exists := objMap[r.name(t)]; exists {
+			continue
 		}
+		child := r.defineObject(t)
+		objMap[child.Name] = child
 	}
 
 	return objMap
 }
 
-// MakeSchema takes struct or pointer to a struct and returns a Schema instance suitable for use by the swagger doc
-func MakeSchema(prototype interface{}) *Schema {
+func (r *reflector) makeSchema(prototype interface{}) *Schema {
 	schema := &Schema{
 		Prototype: prototype,
 	}
 
-	obj := defineObject(prototype)
+	if t := typeOf(prototype); isInline(t) {
+		p := r.inspect(t, "")
+		r.refs = nil // definitions are collected separately by define
+
+		schema.Type = p.Type
+		schema.Format = p.Format
+		schema.Items = p.Items
+		schema.AdditionalProperties = p.AdditionalProperties
+		return schema
+	}
+
+	obj := r.defineObject(prototype)
+	r.refs = nil // definitions are collected separately by define
+
 	if obj.IsArray {
 		schema.Type = "array"
 		schema.Items = &Items{
@@ -227,3 +433,8 @@ func MakeSchema(prototype interface{}) *Schema {
 
 	return schema
 }
+
+// MakeSchema takes struct or pointer to a struct and returns a Schema instance suitable for use by the swagger doc
+func MakeSchema(prototype interface{}) *Schema {
+	return (&reflector{}).makeSchema(prototype)
+}
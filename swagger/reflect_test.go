@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"fmt"
 
@@ -144,3 +145,356 @@ func TestIgnoreUnexported(t *testing.T) {
 	assert.Contains(t, obj.Properties, "Exported")
 	assert.NotContains(t, obj.Properties, "unexported")
 }
+
+type Key string
+
+type Inventory struct {
+	Labels  map[string]string          `json:"labels"`
+	Counts  map[Key]int64              `json:"counts"`
+	Pets    map[string]Person          `json:"pets"`
+	Owners  map[int]*Person            `json:"owners"`
+	Nested  map[string][]string        `json:"nested"`
+	Groups  map[string][]Person        `json:"groups"`
+	Invalid map[struct{}]string        `json:"invalid"`
+	Deep    map[string]map[string]bool `json:"deep"`
+}
+
+func TestMap(t *testing.T) {
+	v := define(Inventory{})
+	obj, ok := v["swaggerInventory"]
+	assert.True(t, ok)
+	assert.Contains(t, v, "swaggerPerson", "expected map values to be defined")
+	assert.Len(t, v, 2)
+
+	labels := obj.Properties["labels"]
+	assert.Equal(t, "object", labels.Type)
+	assert.Equal(t, "string", labels.AdditionalProperties.Type)
+
+	counts := obj.Properties["counts"]
+	assert.Equal(t, "object", counts.Type)
+	assert.Equal(t, "integer", counts.AdditionalProperties.Type)
+	assert.Equal(t, "int64", counts.AdditionalProperties.Format)
+
+	assert.Equal(t, "#/definitions/swaggerPerson", obj.Properties["pets"].AdditionalProperties.Ref)
+	assert.Equal(t, "#/definitions/swaggerPerson", obj.Properties["owners"].AdditionalProperties.Ref)
+
+	nested := obj.Properties["nested"].AdditionalProperties
+	assert.Equal(t, "array", nested.Type)
+	assert.Equal(t, &Items{Type: "string"}, nested.Items)
+
+	groups := obj.Properties["groups"].AdditionalProperties
+	assert.Equal(t, "array", groups.Type)
+	assert.Equal(t, &Items{Ref: "#/definitions/swaggerPerson"}, groups.Items)
+
+	invalid := obj.Properties["invalid"]
+	assert.Equal(t, "object", invalid.Type)
+	assert.Nil(t, invalid.AdditionalProperties)
+
+	deep := obj.Properties["deep"].AdditionalProperties
+	assert.Equal(t, "object", deep.Type)
+	assert.Equal(t, "boolean", deep.AdditionalProperties.Type)
+}
+
+func TestMapSchema(t *testing.T) {
+	schema := MakeSchema(map[string]Person{})
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "", schema.Ref)
+	assert.Equal(t, "#/definitions/swaggerPerson", schema.AdditionalProperties.Ref)
+
+	v := define(map[string]Person{})
+	assert.Len(t, v, 1)
+	assert.Contains(t, v, "swaggerPerson")
+
+	v = define(map[string]string{})
+	assert.Len(t, v, 0)
+}
+
+type Base struct {
+	ID      string `json:"id" required:"true"`
+	Created int64  `json:"created"`
+}
+
+type audit struct {
+	Author string `json:"author"`
+}
+
+type Named string
+
+type Embedded struct {
+	Base
+	*audit
+	Named
+	Name    string `json:"name"`
+	Created string `json:"created"`
+}
+
+type Tagged struct {
+	Base `json:"base"`
+}
+
+type Left struct {
+	Value string
+	Depth string
+}
+
+type Right struct {
+	Value string
+}
+
+type Deeper struct {
+	Left
+}
+
+type Conflict struct {
+	Left
+	Right
+}
+
+type Shadow struct {
+	Deeper
+	Right
+}
+
+func TestEmbedded(t *testing.T) {
+	v := define(Embedded{})
+	obj, ok := v["swaggerEmbedded"]
+	assert.True(t, ok)
+	assert.Len(t, v, 1, "expected embedded structs to be flattened rather than defined")
+	assert.Equal(t, []string{"id"}, obj.Required)
+
+	assert.Len(t, obj.Properties, 5)
+	assert.Equal(t, "string", obj.Properties["id"].Type)
+	assert.Equal(t, "string", obj.Properties["author"].Type, "expected fields of unexported embedded struct")
+	assert.Equal(t, "string", obj.Properties["Named"].Type, "expected embedded non-struct to be a named field")
+	assert.Equal(t, "string", obj.Properties["name"].Type)
+	assert.Equal(t, "string", obj.Properties["created"].Type, "expected outer field to shadow embedded field")
+
+	// the schema should match the actual wire format
+	data, err := json.Marshal(Embedded{audit: &audit{}})
+	assert.Nil(t, err)
+	wire := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &wire))
+	for k := range wire {
+		assert.Contains(t, obj.Properties, k)
+	}
+	assert.Equal(t, len(wire), len(obj.Properties))
+}
+
+func TestEmbeddedTagged(t *testing.T) {
+	v := define(Tagged{})
+	obj := v["swaggerTagged"]
+	assert.Len(t, obj.Properties, 1)
+	assert.Equal(t, "#/definitions/swaggerBase", obj.Properties["base"].Ref)
+	assert.Contains(t, v, "swaggerBase")
+}
+
+func TestEmbeddedConflict(t *testing.T) {
+	obj := defineObject(Conflict{})
+	assert.Len(t, obj.Properties, 1, "expected ambiguous fields at the same depth to be dropped")
+	assert.Contains(t, obj.Properties, "Depth")
+
+	obj = defineObject(Shadow{})
+	assert.Len(t, obj.Properties, 2, "expected shallower field to dominate")
+	assert.Contains(t, obj.Properties, "Value")
+	assert.Contains(t, obj.Properties, "Depth")
+}
+
+func TestRequired(t *testing.T) {
+	type Model struct {
+		ID        string            `json:"id"`
+		Name      string            `json:"name,omitempty"`
+		Nickname  *string           `json:"nickname"`
+		Any       interface{}       `json:"any"`
+		Tags      []string          `json:"tags"`
+		Labels    map[string]string `json:"labels,omitempty"`
+		Optional  string            `json:"optional" required:"false"`
+		Explicit  *string           `json:"explicit,omitempty" required:"true"`
+		Validated *string           `json:"validated,omitempty" validate:"omitempty,required"`
+		OptedOut  string            `json:"optedOut" validate:"required" required:"false"`
+		Untagged  int
+	}
+
+	obj := defineObject(Model{})
+	assert.Equal(t, []string{"explicit"}, obj.Required, "expected validate:\"required\" to be ignored by default")
+
+	r := &reflector{SchemaOptions: SchemaOptions{InferRequired: true}}
+	obj = r.defineObject(Model{})
+	assert.Equal(t, []string{"id", "tags", "explicit", "validated", "Untagged"}, obj.Required)
+}
+
+type Node struct {
+	Parent   *Node              `json:"parent"`
+	Children []*Node            `json:"children"`
+	Leaves   map[string][]*Leaf `json:"leaves"`
+}
+
+type Leaf struct {
+	Grid [][]*Cell `json:"grid"`
+}
+
+type Cell struct {
+	Value string `json:"value"`
+}
+
+type Author struct {
+	Books []Book `json:"books"`
+}
+
+type Book struct {
+	Authors map[string]*Author `json:"authors"`
+}
+
+func TestRecursive(t *testing.T) {
+	v := define(Node{})
+	assert.Len(t, v, 3)
+	assert.Contains(t, v, "swaggerNode")
+	assert.Contains(t, v, "swaggerLeaf")
+	assert.Contains(t, v, "swaggerCell", "expected types nested within slices of slices to be defined")
+	assert.Equal(t, "#/definitions/swaggerNode", v["swaggerNode"].Properties["children"].Items.Ref)
+
+	v = define([]*Author{})
+	assert.Len(t, v, 2)
+	assert.Contains(t, v, "swaggerAuthor")
+	assert.Contains(t, v, "swaggerBook")
+
+	v = define(map[string]Book{})
+	assert.Len(t, v, 2, "expected map values to be defined")
+	assert.Contains(t, v, "swaggerAuthor")
+	assert.Contains(t, v, "swaggerBook")
+}
+
+type Envelope struct {
+	Meta struct {
+		Total int `json:"total" required:"true"`
+	} `json:"meta"`
+	Pages []struct {
+		Cell Cell `json:"cell"`
+	} `json:"pages"`
+}
+
+func TestAnonymousStruct(t *testing.T) {
+	v := define(Envelope{})
+	assert.Len(t, v, 2, "expected anonymous structs to be described inline")
+	assert.Contains(t, v, "swaggerEnvelope")
+	assert.Contains(t, v, "swaggerCell")
+
+	meta := v["swaggerEnvelope"].Properties["meta"]
+	assert.Equal(t, "object", meta.Type)
+	assert.Equal(t, "", meta.Ref)
+	assert.Equal(t, []string{"total"}, meta.Required)
+	assert.Equal(t, "integer", meta.Properties["total"].Type)
+
+	pages := v["swaggerEnvelope"].Properties["pages"]
+	assert.Equal(t, "array", pages.Type)
+	assert.Equal(t, "object", pages.Items.Type)
+	assert.Equal(t, "#/definitions/swaggerCell", pages.Items.Properties["cell"].Ref)
+
+	schema := MakeSchema(struct{ ID string }{})
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "", schema.Ref)
+}
+
+type Polygon struct {
+	Rings    [][][2]float64     `json:"rings" minimum:"-180" maximum:"180"`
+	Counts   []map[string]int   `json:"counts" validate:"dive,dive,min=0"`
+	Pointers *[]*[]*int         `json:"pointers"`
+	Fixed    [4]byte            `json:"fixed"`
+	Cells    [2][]*Cell         `json:"cells"`
+	Lookup   map[string][][]int `json:"lookup"`
+}
+
+func TestNestedArrays(t *testing.T) {
+	v := define(Polygon{})
+	assert.Len(t, v, 2)
+	assert.Contains(t, v, "swaggerCell")
+
+	obj := v["swaggerPolygon"]
+
+	rings := obj.Properties["rings"]
+	assert.Equal(t, "array", rings.Type)
+	assert.Equal(t, "array", rings.Items.Type)
+	assert.Equal(t, "array", rings.Items.Items.Type)
+	assert.Equal(t, int64(2), *rings.Items.Items.MinItems)
+	assert.Equal(t, int64(2), *rings.Items.Items.MaxItems)
+	point := rings.Items.Items.Items
+	assert.Equal(t, "number", point.Type)
+	assert.Equal(t, "double", point.Format)
+	assert.Equal(t, -180.0, *point.Minimum, "expected constraints to apply to the innermost items")
+	assert.Equal(t, 180.0, *point.Maximum)
+
+	counts := obj.Properties["counts"]
+	assert.Equal(t, "object", counts.Items.Type)
+	assert.Equal(t, "integer", counts.Items.AdditionalProperties.Type)
+	assert.Equal(t, 0.0, *counts.Items.AdditionalProperties.Minimum)
+
+	pointers := obj.Properties["pointers"]
+	assert.Equal(t, "array", pointers.Items.Type)
+	assert.Equal(t, "integer", pointers.Items.Items.Type)
+
+	fixed := obj.Properties["fixed"]
+	assert.Equal(t, "array", fixed.Type, "expected byte arrays, unlike byte slices, to be arrays")
+	assert.Equal(t, "integer", fixed.Items.Type)
+	assert.Equal(t, int64(4), *fixed.MinItems)
+	assert.Equal(t, int64(4), *fixed.MaxItems)
+
+	cells := obj.Properties["cells"]
+	assert.Equal(t, "#/definitions/swaggerCell", cells.Items.Items.Ref)
+
+	lookup := obj.Properties["lookup"]
+	assert.Equal(t, "integer", lookup.AdditionalProperties.Items.Items.Type)
+}
+
+func TestNestedArraySchema(t *testing.T) {
+	schema := MakeSchema([][]float64{})
+	assert.Equal(t, "array", schema.Type)
+	assert.Equal(t, "array", schema.Items.Type)
+	assert.Equal(t, "number", schema.Items.Items.Type)
+	assert.Equal(t, "", schema.Items.Ref)
+
+	schema = MakeSchema(&[]map[string]*Cell{})
+	assert.Equal(t, "array", schema.Type)
+	assert.Equal(t, "object", schema.Items.Type)
+	assert.Equal(t, "#/definitions/swaggerCell", schema.Items.AdditionalProperties.Ref)
+
+	v := define([]map[string]*Cell{})
+	assert.Len(t, v, 1)
+	assert.Contains(t, v, "swaggerCell")
+
+	schema = MakeSchema([3]Cell{})
+	assert.Equal(t, "array", schema.Type)
+	assert.Equal(t, "#/definitions/swaggerCell", schema.Items.Ref)
+
+	data, err := json.Marshal(MakeSchema([][]string{}))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"array","items":{"type":"array","items":{"type":"string"}}}`, string(data))
+
+	data, err = json.Marshal(MakeSchema([]time.Time{}))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"array","items":{"type":"string","format":"date-time"}}`, string(data))
+
+	data, err = json.Marshal(MakeSchema([]byte{}))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"string","format":"byte"}`, string(data))
+
+	data, err = json.Marshal(MakeSchema(""))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"string"}`, string(data))
+
+	data, err = json.Marshal(MakeSchema(&time.Time{}))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"type":"string","format":"date-time"}`, string(data))
+
+	api := &API{}
+	api.AddEndpoint(&Endpoint{
+		Method: "GET",
+		Path:   "/names",
+		Responses: map[string]Response{
+			"200": {Schema: &Schema{Prototype: []string{}}},
+			"201": {Schema: &Schema{Prototype: ""}},
+			"202": {Schema: &Schema{Prototype: time.Time{}}},
+		},
+	})
+	assert.Equal(t, &Schema{Type: "array", Items: &Items{Type: "string"}, Prototype: []string{}},
+		api.Paths["/names"].Get.Responses["200"].Schema)
+	assert.Empty(t, api.Definitions, "expected primitives, well-known types, and arrays of them to require no definitions")
+}
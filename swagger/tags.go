@@ -0,0 +1,155 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The following struct tags are read by define to document the properties of a definition:
+//
+//	description:"..."  sets the property description
+//	example:"..."      sets the property example
+//	default:"..."      sets the property default
+//	enum:"a,b,c"       sets the comma separated list of allowed values
+//	format:"uuid"      overrides the format derived from the go type
+//	readOnly:"true"    marks the property as only sent in responses e.g. server assigned identifiers
+//	writeOnly:"true"   marks the property as only sent in requests e.g. passwords
+//	nullable:"true"    marks the property as accepting null; overrides the nullability inferred for pointers
+//
+// example, default, and enum values are converted to the type of the property e.g. integers for integer properties.
+// Values for array and object properties may be specified as json; arrays may also be specified as a comma separated
+// list.  For arrays, format and enum apply to the innermost array items
+const (
+	tagDescription = "description"
+	tagExample     = "example"
+	tagDefault     = "default"
+	tagEnum        = "enum"
+	tagFormat      = "format"
+	tagReadOnly    = "readOnly"
+	tagWriteOnly   = "writeOnly"
+	tagNullable    = "nullable"
+)
+
+// parseValue converts the tag value, v, into a value of the swagger type, typ.  Values that cannot be converted are
+// returned as is
+func parseValue(typ string, v string) interface{} {
+	switch typ {
+	case "integer":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+
+	case "string":
+		return v
+
+	default:
+		var value interface{}
+		if err := json.Unmarshal([]byte(v), &value); err == nil {
+			return value
+		}
+	}
+
+	return v
+}
+
+// parseList splits the comma separated tag value and converts each element into the swagger type, typ
+func parseList(typ string, v string) []interface{} {
+	values := []interface{}{}
+	for _, item := range strings.Split(v, ",") {
+		values = append(values, parseValue(typ, strings.TrimSpace(item)))
+	}
+	return values
+}
+
+// parseProperty converts the tag value, v, into a value suitable for the example or default of the property
+func parseProperty(p *Property, v string) interface{} {
+	if p.Type == "array" && p.Items != nil && !strings.HasPrefix(strings.TrimSpace(v), "[") {
+		return parseList(p.Items.Type, v)
+	}
+	return parseValue(p.Type, v)
+}
+
+// copyItems returns a copy of items and of the items nested within them
+func copyItems(items *Items) *Items {
+	if items == nil {
+		return nil
+	}
+	c := *items
+	c.Items = copyItems(items.Items)
+	return &c
+}
+
+// applyTags customizes the property using the schema struct tags of the field
+func applyTags(p *Property, tag reflect.StructTag) {
+	// items and additional properties may be shared with a registered type
+	p.Items = copyItems(p.Items)
+	if p.AdditionalProperties != nil {
+		additionalProperties := *p.AdditionalProperties
+		p.AdditionalProperties = &additionalProperties
+	}
+
+	if v := tag.Get(tagDescription); v != "" {
+		p.Description = v
+	}
+
+	if v := tag.Get(tagFormat); v != "" {
+		if p.Type == "array" && p.Items != nil {
+			leafItems(p).Format = v
+		} else {
+			p.Format = v
+		}
+	}
+
+	if v := tag.Get(tagEnum); v != "" {
+		if p.Type == "array" && p.Items != nil {
+			items := leafItems(p)
+			items.Enum = parseList(items.Type, v)
+		} else {
+			p.Enum = parseList(p.Type, v)
+		}
+	}
+
+	if v := tag.Get(tagExample); v != "" {
+		p.Example = parseProperty(p, v)
+	}
+
+	if v := tag.Get(tagDefault); v != "" {
+		p.Default = parseProperty(p, v)
+	}
+
+	if v := tag.Get(tagReadOnly); v != "" {
+		p.ReadOnly, _ = strconv.ParseBool(v)
+	}
+	if v := tag.Get(tagWriteOnly); v != "" {
+		p.WriteOnly, _ = strconv.ParseBool(v)
+	}
+	if v := tag.Get(tagNullable); v != "" {
+		p.Nullable, _ = strconv.ParseBool(v)
+	}
+}
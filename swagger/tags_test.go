@@ -0,0 +1,170 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Documented struct {
+	ID       string            `json:"id" description:"unique identifier" format:"uuid" example:"9b2f4f0a-7c50-4c38-9d4e-5a5b6f2a8f0e"`
+	Age      int               `json:"age" description:"age in years" example:"42" default:"1"`
+	Weight   float64           `json:"weight" example:"12.5"`
+	Active   bool              `json:"active" default:"true"`
+	Status   string            `json:"status" enum:"available, pending,sold" default:"available"`
+	Level    int               `json:"level" enum:"1,2,3"`
+	Tags     []string          `json:"tags" enum:"a,b" example:"a,b" format:"tag"`
+	Scores   []int             `json:"scores" example:"[1, 2]" default:"3,4"`
+	Owner    Person            `json:"owner" example:"{\"First\":\"joe\"}"`
+	Labels   map[string]string `json:"labels" example:"{\"env\":\"prod\"}"`
+	Invalid  int               `json:"invalid" example:"abc"`
+	Untagged string            `json:"untagged"`
+}
+
+func TestTags(t *testing.T) {
+	obj := defineObject(Documented{})
+
+	id := obj.Properties["id"]
+	assert.Equal(t, "unique identifier", id.Description)
+	assert.Equal(t, "uuid", id.Format)
+	assert.Equal(t, "9b2f4f0a-7c50-4c38-9d4e-5a5b6f2a8f0e", id.Example)
+
+	age := obj.Properties["age"]
+	assert.Equal(t, "age in years", age.Description)
+	assert.Equal(t, "int32", age.Format)
+	assert.Equal(t, int64(42), age.Example)
+	assert.Equal(t, int64(1), age.Default)
+
+	assert.Equal(t, 12.5, obj.Properties["weight"].Example)
+	assert.Equal(t, true, obj.Properties["active"].Default)
+
+	status := obj.Properties["status"]
+	assert.Equal(t, []interface{}{"available", "pending", "sold"}, status.Enum)
+	assert.Equal(t, "available", status.Default)
+
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, obj.Properties["level"].Enum)
+
+	tags := obj.Properties["tags"]
+	assert.Nil(t, tags.Enum)
+	assert.Equal(t, "", tags.Format)
+	assert.Equal(t, &Items{Type: "string", Format: "tag", Enum: []interface{}{"a", "b"}}, tags.Items)
+	assert.Equal(t, []interface{}{"a", "b"}, tags.Example)
+
+	scores := obj.Properties["scores"]
+	assert.Equal(t, []interface{}{1.0, 2.0}, scores.Example)
+	assert.Equal(t, []interface{}{int64(3), int64(4)}, scores.Default)
+
+	assert.Equal(t, map[string]interface{}{"First": "joe"}, obj.Properties["owner"].Example)
+	assert.Equal(t, map[string]interface{}{"env": "prod"}, obj.Properties["labels"].Example)
+	assert.Equal(t, "abc", obj.Properties["invalid"].Example)
+
+	untagged := obj.Properties["untagged"]
+	assert.Equal(t, "", untagged.Description)
+	assert.Nil(t, untagged.Example)
+	assert.Nil(t, untagged.Default)
+	assert.Nil(t, untagged.Enum)
+}
+
+func TestTagsRegisteredItems(t *testing.T) {
+	type Shared struct{}
+	type Model struct {
+		A Shared `json:"a" format:"one"`
+		B Shared `json:"b"`
+	}
+
+	Register(Shared{}, Property{Type: "array", Items: &Items{Type: "string"}})
+	defer func() {
+		typesMux.Lock()
+		delete(types, reflect.TypeOf(Shared{}))
+		typesMux.Unlock()
+	}()
+
+	obj := defineObject(Model{})
+	assert.Equal(t, "one", obj.Properties["a"].Items.Format)
+	assert.Equal(t, "", obj.Properties["b"].Items.Format, "expected registered items to be left unmodified")
+}
+
+func TestTagsAccess(t *testing.T) {
+	type Model struct {
+		ID       string  `json:"id" readOnly:"true"`
+		Password string  `json:"password" writeOnly:"true"`
+		Nickname *string `json:"nickname"`
+		Parent   *string `json:"parent" nullable:"false"`
+		Deleted  string  `json:"deleted" nullable:"true"`
+	}
+
+	obj := defineObject(Model{})
+	assert.True(t, obj.Properties["id"].ReadOnly)
+	assert.True(t, obj.Properties["password"].WriteOnly)
+	assert.False(t, obj.Properties["nickname"].Nullable, "expected nullability to be inferred only when enabled")
+	assert.True(t, obj.Properties["deleted"].Nullable)
+
+	r := &reflector{SchemaOptions: SchemaOptions{InferNullable: true}}
+	obj = r.defineObject(Model{})
+	assert.True(t, obj.Properties["nickname"].Nullable)
+	assert.False(t, obj.Properties["parent"].Nullable)
+	assert.False(t, obj.Properties["id"].Nullable)
+
+	data, err := json.Marshal(obj.Properties)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id":       {"type": "string", "readOnly": true},
+		"password": {"type": "string"},
+		"nickname": {"type": "string", "x-nullable": true},
+		"parent":   {"type": "string"},
+		"deleted":  {"type": "string", "x-nullable": true}
+	}`, string(data))
+
+	data, err = json.Marshal(openAPIObject(obj).Properties)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id":       {"type": "string", "readOnly": true},
+		"password": {"type": "string", "writeOnly": true},
+		"nickname": {"type": "string", "nullable": true},
+		"parent":   {"type": "string"},
+		"deleted":  {"type": "string", "nullable": true}
+	}`, string(data))
+}
+
+func TestTagsAccessRef(t *testing.T) {
+	type Model struct {
+		Owner   *Person `json:"owner"`
+		Creator Person  `json:"creator" readOnly:"true"`
+		Friend  Person  `json:"friend"`
+	}
+
+	r := &reflector{SchemaOptions: SchemaOptions{InferNullable: true}}
+	obj := r.defineObject(Model{})
+
+	data, err := json.Marshal(obj.Properties)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"owner":   {"allOf": [{"$ref": "#/definitions/swaggerPerson"}], "x-nullable": true},
+		"creator": {"allOf": [{"$ref": "#/definitions/swaggerPerson"}], "readOnly": true},
+		"friend":  {"$ref": "#/definitions/swaggerPerson"}
+	}`, string(data), "expected references with markers to be wrapped in allOf")
+
+	data, err = json.Marshal(openAPIObject(obj).Properties)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"owner":   {"allOf": [{"$ref": "#/components/schemas/swaggerPerson"}], "nullable": true},
+		"creator": {"allOf": [{"$ref": "#/components/schemas/swaggerPerson"}], "readOnly": true},
+		"friend":  {"$ref": "#/components/schemas/swaggerPerson"}
+	}`, string(data))
+}
@@ -0,0 +1,25 @@
+package swagger
+
+// Commented is a documented model
+type Commented struct {
+	// ID uniquely identifies the model
+	ID string `json:"id"`
+
+	Name string `json:"name"` // Name is trailing
+
+	// Tagged is overridden by its tag
+	Tagged string `json:"tagged" description:"from the tag"`
+
+	// A and B share a comment
+	A, B int
+
+	Undocumented string
+
+	CommentedBase
+}
+
+// CommentedBase is embedded
+type CommentedBase struct {
+	// Created is promoted
+	Created int64 `json:"created"`
+}
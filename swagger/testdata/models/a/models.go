@@ -0,0 +1,6 @@
+package models
+
+// User is declared by package a
+type User struct {
+	Name string
+}
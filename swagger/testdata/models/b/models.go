@@ -0,0 +1,6 @@
+package models
+
+// User is declared by package b
+type User struct {
+	Name string
+}
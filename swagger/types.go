@@ -0,0 +1,123 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Describer may be implemented by types that wish to describe their own schema rather than have one derived via
+// reflection.  SwaggerProperty is invoked on the zero value of the type
+type Describer interface {
+	SwaggerProperty() Property
+}
+
+var (
+	describerType     = reflect.TypeOf((*Describer)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+var (
+	typesMux = &sync.RWMutex{}
+
+	// types holds the schemas of types whose json encoding differs from their go structure.  Types such as url.URL and
+	// sql.NullString are encoded by encoding/json as structs and so are described by their go structure
+	types = map[reflect.Type]Property{
+		reflect.TypeOf(time.Time{}):                {Type: "string", Format: "date-time"},
+		reflect.TypeOf(time.Duration(0)):           {Type: "integer", Format: "int64"},
+		reflect.TypeOf([]byte{}):                   {Type: "string", Format: "byte"},
+		reflect.TypeOf(json.RawMessage{}):          {Type: "object"},
+		reflect.TypeOf((*interface{})(nil)).Elem(): {Type: "object"},
+		reflect.TypeOf(net.IP{}):                   {Type: "string"},
+		reflect.TypeOf(big.Int{}):                  {Type: "integer"},
+		reflect.TypeOf(big.Float{}):                {Type: "string"},
+		reflect.TypeOf(big.Rat{}):                  {Type: "string"},
+	}
+)
+
+// RegisterType maps the go type, t, to the specified property.  Registered types are described inline wherever they
+// are used rather than by reference to a definition.  Useful for types such as UUIDs or decimals whose wire format
+// differs from their go structure
+func RegisterType(t reflect.Type, p Property) {
+	typesMux.Lock()
+	defer typesMux.Unlock()
+
+	p.GoType = nil
+	types[t] = p
+}
+
+// Register maps the type of prototype to the specified property; see RegisterType
+func Register(prototype interface{}, p Property) {
+	RegisterType(reflect.TypeOf(prototype), p)
+}
+
+// lookupType returns the registered property for t, if any
+func lookupType(t reflect.Type) (Property, bool) {
+	typesMux.RLock()
+	defer typesMux.RUnlock()
+
+	p, ok := types[t]
+	return p, ok
+}
+
+// implements returns true if either t or a pointer to t implements the interface, u
+func implements(t, u reflect.Type) bool {
+	return t.Implements(u) || reflect.PtrTo(t).Implements(u)
+}
+
+// wellKnown returns the property for types that are not described by their go structure; registered types, types
+// that implement Describer, and types that implement json.Marshaler or encoding.TextMarshaler, which are assumed to
+// be strings
+func wellKnown(t reflect.Type) (Property, bool) {
+	if p, ok := lookupType(t); ok {
+		return p, true
+	}
+
+	// pointers are dereferenced by inspect prior to reaching here
+	if t.Kind() == reflect.Ptr {
+		return Property{}, false
+	}
+
+	// the zero value of an interface is nil and so cannot describe itself
+	if t.Kind() != reflect.Interface && t.Implements(describerType) {
+		return reflect.Zero(t).Interface().(Describer).SwaggerProperty(), true
+	}
+	if reflect.PtrTo(t).Implements(describerType) {
+		return reflect.New(t).Interface().(Describer).SwaggerProperty(), true
+	}
+
+	if implements(t, jsonMarshalerType) || implements(t, textMarshalerType) {
+		return Property{Type: "string"}, true
+	}
+
+	return Property{}, false
+}
+
+// isObject returns true if t should be described by its own definition; anonymous structs are described inline
+func isObject(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return false
+	}
+
+	_, ok := wellKnown(t)
+	return !ok
+}
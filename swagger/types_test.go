@@ -0,0 +1,238 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type WellKnown struct {
+	Time      time.Time        `json:"time"`
+	TimePtr   *time.Time       `json:"timePtr"`
+	Times     []time.Time      `json:"times"`
+	Duration  time.Duration    `json:"duration"`
+	Bytes     []byte           `json:"bytes"`
+	Raw       json.RawMessage  `json:"raw"`
+	Any       interface{}      `json:"any"`
+	IP        net.IP           `json:"ip"`
+	Big       *big.Int         `json:"big"`
+	Float     big.Float        `json:"float"`
+	ByName    map[string][]int `json:"byName"`
+	StringPtr *string          `json:"stringPtr"`
+}
+
+func TestWellKnownTypes(t *testing.T) {
+	v := define(WellKnown{})
+	assert.Len(t, v, 1, "expected well known types to be described inline")
+
+	obj := v["swaggerWellKnown"]
+	testCases := map[string]Property{
+		"time":      {Type: "string", Format: "date-time"},
+		"timePtr":   {Type: "string", Format: "date-time"},
+		"duration":  {Type: "integer", Format: "int64"},
+		"bytes":     {Type: "string", Format: "byte"},
+		"raw":       {Type: "object"},
+		"any":       {Type: "object"},
+		"ip":        {Type: "string"},
+		"big":       {Type: "integer"},
+		"float":     {Type: "string"},
+		"stringPtr": {Type: "string"},
+	}
+	for name, expected := range testCases {
+		p := obj.Properties[name]
+		assert.Equal(t, expected.Type, p.Type, "expected %v.Type to match", name)
+		assert.Equal(t, expected.Format, p.Format, "expected %v.Format to match", name)
+		assert.Equal(t, "", p.Ref, "expected %v.Ref to be empty", name)
+	}
+
+	times := obj.Properties["times"]
+	assert.Equal(t, "array", times.Type)
+	assert.Equal(t, &Items{Type: "string", Format: "date-time"}, times.Items)
+}
+
+// jsonType returns the swagger type of the decoded json value, v
+func jsonType(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return "null"
+}
+
+func TestWellKnownEncoding(t *testing.T) {
+	samples := map[reflect.Type]interface{}{
+		reflect.TypeOf(time.Time{}):                time.Now(),
+		reflect.TypeOf(time.Duration(0)):           time.Second,
+		reflect.TypeOf([]byte{}):                   []byte("data"),
+		reflect.TypeOf(json.RawMessage{}):          json.RawMessage(`{"a":1}`),
+		reflect.TypeOf((*interface{})(nil)).Elem(): map[string]interface{}{"a": 1},
+		reflect.TypeOf(net.IP{}):                   net.ParseIP("127.0.0.1"),
+		reflect.TypeOf(big.Int{}):                  big.NewInt(12),
+		reflect.TypeOf(big.Float{}):                big.NewFloat(1.5),
+		reflect.TypeOf(big.Rat{}):                  big.NewRat(1, 2),
+	}
+
+	typesMux.RLock()
+	defer typesMux.RUnlock()
+
+	for typ, p := range types {
+		sample, ok := samples[typ]
+		if !assert.True(t, ok, "expected a sample of %v", typ) {
+			continue
+		}
+
+		data, err := json.Marshal(sample)
+		assert.Nil(t, err)
+
+		var v interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		assert.Nil(t, decoder.Decode(&v))
+		assert.Equal(t, p.Type, jsonType(v), "expected %v to be described by its json encoding, %s", typ, data)
+	}
+}
+
+type UUID [16]byte
+
+type Money struct {
+	Units int64
+	Nanos int32
+}
+
+func TestRegisterType(t *testing.T) {
+	type Order struct {
+		ID     UUID   `json:"id"`
+		Amount *Money `json:"amount"`
+	}
+
+	Register(UUID{}, Property{Type: "string", Format: "uuid"})
+	RegisterType(reflect.TypeOf(Money{}), Property{Type: "string", Format: "decimal", Example: "12.50"})
+	defer func() {
+		typesMux.Lock()
+		delete(types, reflect.TypeOf(UUID{}))
+		delete(types, reflect.TypeOf(Money{}))
+		typesMux.Unlock()
+	}()
+
+	v := define(Order{})
+	assert.Len(t, v, 1)
+
+	obj := v["swaggerOrder"]
+	assert.Equal(t, "uuid", obj.Properties["id"].Format)
+	assert.Equal(t, "string", obj.Properties["amount"].Type)
+	assert.Equal(t, "decimal", obj.Properties["amount"].Format)
+	assert.Equal(t, "12.50", obj.Properties["amount"].Example)
+
+	obj = defineObject(Money{})
+	assert.Equal(t, "swaggerMoney", obj.Name)
+	assert.Equal(t, "string", obj.Type)
+}
+
+type Status int
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte("active"), nil
+}
+
+type Timestamp struct {
+	seconds int64
+}
+
+func (t *Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"2017-01-01T00:00:00Z"`), nil
+}
+
+type Color struct {
+	R, G, B uint8
+}
+
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte("#000000"), nil
+}
+
+func (c Color) SwaggerProperty() Property {
+	return Property{Type: "string", Format: "color", Example: "#ff0000"}
+}
+
+type Level int
+
+func (l *Level) SwaggerProperty() Property {
+	return Property{Type: "integer", Enum: []interface{}{1, 2, 3}}
+}
+
+func TestMarshalers(t *testing.T) {
+	type Account struct {
+		Status    Status            `json:"status"`
+		Statuses  []Status          `json:"statuses"`
+		Created   Timestamp         `json:"created"`
+		Updated   *Timestamp        `json:"updated"`
+		Color     Color             `json:"color"`
+		Level     Level             `json:"level"`
+		ByStatus  map[Status]string `json:"byStatus"`
+		Nickname  string            `json:"nickname"`
+		Reference Person            `json:"reference"`
+	}
+
+	v := define(Account{})
+	assert.Len(t, v, 2, "expected only Account and Person to be defined")
+	assert.Contains(t, v, "swaggerPerson")
+
+	obj := v["swaggerAccount"]
+	assert.Equal(t, "string", obj.Properties["status"].Type)
+	assert.Equal(t, "", obj.Properties["status"].Format)
+	assert.Equal(t, &Items{Type: "string"}, obj.Properties["statuses"].Items)
+	assert.Equal(t, "string", obj.Properties["created"].Type)
+	assert.Equal(t, "string", obj.Properties["updated"].Type)
+	assert.Equal(t, "", obj.Properties["updated"].Ref)
+	assert.Equal(t, "color", obj.Properties["color"].Format, "expected Describer to take precedence over marshalers")
+	assert.Equal(t, "#ff0000", obj.Properties["color"].Example)
+	assert.Equal(t, "integer", obj.Properties["level"].Type)
+	assert.Equal(t, []interface{}{1, 2, 3}, obj.Properties["level"].Enum)
+	assert.Equal(t, "string", obj.Properties["byStatus"].AdditionalProperties.Type)
+}
+
+type Shape interface {
+	Describer
+	Area() float64
+}
+
+func TestDescriberInterface(t *testing.T) {
+	type Drawing struct {
+		Shape Shape `json:"shape"`
+	}
+
+	assert.NotPanics(t, func() {
+		defineObject(Drawing{})
+	}, "expected interfaces that embed Describer to be ignored")
+}
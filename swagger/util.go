@@ -20,10 +20,25 @@ import (
 	"strings"
 )
 
+const (
+	parametersPrefix = "#/parameters/"
+	responsesPrefix  = "#/responses/"
+)
+
 func makeRef(name string) string {
 	return fmt.Sprintf("#/definitions/%v", name)
 }
 
+// MakeParameterRef returns the $ref of the api parameter, name; see API.AddParameter
+func MakeParameterRef(name string) string {
+	return parametersPrefix + name
+}
+
+// MakeResponseRef returns the $ref of the api response, name; see API.AddResponse
+func MakeResponseRef(name string) string {
+	return responsesPrefix + name
+}
+
 type reflectType interface {
 	PkgPath() string
 	Name() string
@@ -0,0 +1,473 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity distinguishes problems that make the api invalid from those that merely deserve attention
+type Severity string
+
+const (
+	// SeverityError indicates the api violates the swagger specification or references something that does not exist
+	SeverityError Severity = "error"
+
+	// SeverityWarning indicates the api is valid, but likely not as intended
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationCode identifies the kind of problem found by Validate
+type ValidationCode string
+
+const (
+	// UnknownPathParameter indicates a path parameter that does not appear in the path template
+	UnknownPathParameter ValidationCode = "unknown-path-parameter"
+
+	// MissingPathParameter indicates a placeholder in the path template with no matching path parameter
+	MissingPathParameter ValidationCode = "missing-path-parameter"
+
+	// DuplicateOperationID indicates an operationId used by more than one endpoint
+	DuplicateOperationID ValidationCode = "duplicate-operation-id"
+
+	// UndefinedSecurityScheme indicates a security requirement that references an undefined security definition
+	UndefinedSecurityScheme ValidationCode = "undefined-security-scheme"
+
+	// UndeclaredTag indicates an endpoint tag that is not declared by the api
+	UndeclaredTag ValidationCode = "undeclared-tag"
+
+	// MissingDefinition indicates a $ref to a definition that does not exist
+	MissingDefinition ValidationCode = "missing-definition"
+
+	// MissingParameter indicates a $ref to an api parameter that does not exist
+	MissingParameter ValidationCode = "missing-parameter"
+
+	// MissingResponse indicates a $ref to an api response that does not exist
+	MissingResponse ValidationCode = "missing-response"
+
+	// OmittedParameter indicates a cookie parameter, which swagger 2.0 is unable to describe; it is omitted from the
+	// swagger document and described only by the OpenAPI 3.0 document
+	OmittedParameter ValidationCode = "omitted-parameter"
+
+	// UnconsumedForm indicates form parameters on an endpoint that consumes neither application/x-www-form-urlencoded
+	// nor multipart/form-data
+	UnconsumedForm ValidationCode = "unconsumed-form"
+
+	// MissingItems indicates an array parameter, or array items of a parameter, that does not describe its items
+	MissingItems ValidationCode = "missing-items"
+
+	// InvalidSecurityScheme indicates a security scheme configured by an invalid option e.g. APIKeySecurity with an
+	// unknown location
+	InvalidSecurityScheme ValidationCode = "invalid-security-scheme"
+
+	// InvalidName indicates a definition whose name is empty or cannot be used, unescaped, in a $ref
+	InvalidName ValidationCode = "invalid-name"
+)
+
+// ValidationError describes a single problem found by Validate
+type ValidationError struct {
+	Severity Severity       `json:"severity"`
+	Code     ValidationCode `json:"code"`
+
+	// Location is the json pointer of the offending element e.g. #/paths/~1pets~1{id}/get/parameters/0
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", e.Severity, e.Location, e.Message)
+}
+
+// ValidationErrors holds the problems found by Validate in the order they were found
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Errors returns only the problems that make the api invalid
+func (e ValidationErrors) Errors() ValidationErrors {
+	return e.filter(SeverityError)
+}
+
+// Warnings returns only the problems that merely deserve attention
+func (e ValidationErrors) Warnings() ValidationErrors {
+	return e.filter(SeverityWarning)
+}
+
+func (e ValidationErrors) filter(severity Severity) ValidationErrors {
+	var filtered ValidationErrors
+	for _, err := range e {
+		if err.Severity == severity {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// pointer returns the json pointer composed of the specified reference tokens
+func pointer(tokens ...string) string {
+	escaped := make([]string, 0, len(tokens)+1)
+	escaped = append(escaped, "#")
+	for _, token := range tokens {
+		escaped = append(escaped, pointerEscaper.Replace(token))
+	}
+	return strings.Join(escaped, "/")
+}
+
+var placeholderPattern = regexp.MustCompile(`{([^{}]+)}`)
+
+// validator accumulates the problems found while validating an api
+type validator struct {
+	api    *API
+	errors ValidationErrors
+}
+
+func (v *validator) add(severity Severity, code ValidationCode, location, format string, args ...interface{}) {
+	v.errors = append(v.errors, ValidationError{
+		Severity: severity,
+		Code:     code,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) security(s *SecurityRequirement, tokens ...string) {
+	if s == nil {
+		return
+	}
+
+	for i, requirement := range s.Requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if _, ok := v.api.SecurityDefinitions[name]; !ok {
+				location := pointer(append(tokens, "security", strconv.Itoa(i), name)...)
+				v.add(SeverityError, UndefinedSecurityScheme, location, "security scheme, %v, is not defined", name)
+			}
+		}
+	}
+}
+
+func (v *validator) ref(ref string, tokens ...string) {
+	location := pointer(append(tokens, "$ref")...)
+
+	switch {
+	case strings.HasPrefix(ref, definitionsPrefix):
+		name := ref[len(definitionsPrefix):]
+		if _, ok := v.api.Definitions[name]; !ok {
+			v.add(SeverityError, MissingDefinition, location, "definition, %v, does not exist", name)
+		}
+
+	case strings.HasPrefix(ref, parametersPrefix):
+		name := ref[len(parametersPrefix):]
+		if _, ok := v.api.Parameters[name]; !ok {
+			v.add(SeverityError, MissingParameter, location, "parameter, %v, does not exist", name)
+		}
+
+	case strings.HasPrefix(ref, responsesPrefix):
+		name := ref[len(responsesPrefix):]
+		if _, ok := v.api.Responses[name]; !ok {
+			v.add(SeverityError, MissingResponse, location, "response, %v, does not exist", name)
+		}
+	}
+}
+
+// resolve returns the api parameter referenced by p, or p itself if it is not a reference
+func (v *validator) resolve(p Parameter) Parameter {
+	if p.Ref == "" {
+		return p
+	}
+	return v.api.Parameters[strings.TrimPrefix(p.Ref, parametersPrefix)]
+}
+
+func (v *validator) items(items *Items, tokens ...string) {
+	if items == nil {
+		return
+	}
+
+	tokens = append(tokens, "items")
+	v.ref(items.Ref, tokens...)
+	v.items(items.Items, tokens...)
+	v.property(items.AdditionalProperties, append(tokens, "additionalProperties")...)
+	v.properties(items.Properties, tokens...)
+}
+
+func (v *validator) properties(properties map[string]Property, tokens ...string) {
+	for _, name := range sortedProperties(properties) {
+		p := properties[name]
+		v.property(&p, append(tokens, "properties", name)...)
+	}
+}
+
+func (v *validator) property(p *Property, tokens ...string) {
+	if p == nil {
+		return
+	}
+
+	v.ref(p.Ref, tokens...)
+	v.items(p.Items, tokens...)
+	v.property(p.AdditionalProperties, append(tokens, "additionalProperties")...)
+	v.properties(p.Properties, tokens...)
+
+	for i, child := range p.AllOf {
+		child := child
+		v.property(&child, append(tokens, "allOf", strconv.Itoa(i))...)
+	}
+}
+
+func (v *validator) schema(s *Schema, tokens ...string) {
+	if s == nil {
+		return
+	}
+
+	v.ref(s.Ref, tokens...)
+	v.items(s.Items, tokens...)
+	v.property(s.AdditionalProperties, append(tokens, "additionalProperties")...)
+	v.properties(s.Properties, tokens...)
+
+	for i, child := range s.AllOf {
+		child := child
+		v.property(&child, append(tokens, "allOf", strconv.Itoa(i))...)
+	}
+}
+
+func (v *validator) object(obj Object, tokens ...string) {
+	v.ref(obj.Ref, tokens...)
+	v.items(obj.Items, tokens...)
+
+	v.properties(obj.Properties, tokens...)
+	v.property(obj.AdditionalProperties, append(tokens, "additionalProperties")...)
+
+	for i, child := range obj.AllOf {
+		v.object(child, append(tokens, "allOf", strconv.Itoa(i))...)
+	}
+}
+
+func sortedProperties(properties map[string]Property) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parameter validates the parameter, p, of the path and returns the api parameter it references, if any
+func (v *validator) parameter(p Parameter, path string, tokens ...string) Parameter {
+	v.ref(p.Ref, tokens...)
+	v.parameterItems(p, tokens...)
+
+	p = v.resolve(p)
+	if p.In == "cookie" {
+		v.add(SeverityWarning, OmittedParameter, pointer(tokens...),
+			"cookie parameter, %v, is omitted from the swagger 2.0 document", p.Name)
+	}
+	if p.In == "path" && !strings.Contains(path, "{"+p.Name+"}") {
+		v.add(SeverityError, UnknownPathParameter, pointer(tokens...),
+			"path parameter, %v, does not appear in path, %v", p.Name, path)
+	}
+	v.schema(p.Schema, append(tokens, "schema")...)
+
+	return p
+}
+
+// endpoint validates the endpoint, e, which inherits the path parameters, shared
+func (v *validator) endpoint(e *Endpoint, shared []Parameter, operationIDs map[string]string, tags map[string]bool,
+	tokens ...string) {
+	declared := map[string]bool{}
+	form := false
+	for _, p := range inherit(shared, e.Parameters, v.api.Parameters) {
+		p = v.resolve(p)
+		if p.In == "formData" {
+			form = true
+		}
+		if p.In == "path" {
+			declared[p.Name] = true
+		}
+	}
+
+	for i, p := range e.Parameters {
+		v.parameter(p, e.Path, append(tokens, "parameters", strconv.Itoa(i))...)
+	}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(e.Path, -1) {
+		if !declared[match[1]] {
+			v.add(SeverityError, MissingPathParameter, pointer(tokens...),
+				"path, %v, has no parameter for placeholder, %v", e.Path, match[1])
+		}
+	}
+
+	if form && !hasFormMediaType(e.Consumes) {
+		v.add(SeverityError, UnconsumedForm, pointer(append(tokens, "consumes")...),
+			"endpoint has form parameters, but consumes neither application/x-www-form-urlencoded nor multipart/form-data")
+	}
+
+	if e.OperationID != "" {
+		location := pointer(append(tokens, "operationId")...)
+		if first, ok := operationIDs[e.OperationID]; ok {
+			v.add(SeverityError, DuplicateOperationID, location, "operationId, %v, is also used by %v", e.OperationID, first)
+		} else {
+			operationIDs[e.OperationID] = location
+		}
+	}
+
+	for i, tag := range e.Tags {
+		if !tags[tag] {
+			v.add(SeverityWarning, UndeclaredTag, pointer(append(tokens, "tags", strconv.Itoa(i))...), "tag, %v, is not declared", tag)
+		}
+	}
+
+	v.security(e.Security, tokens...)
+
+	codes := make([]string, 0, len(e.Responses))
+	for code := range e.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		v.response(e.Responses[code], append(tokens, "responses", code)...)
+	}
+}
+
+// parameterItems reports array parameters, and array items of parameters, that do not describe their items; body
+// parameters are described by their schema instead
+func (v *validator) parameterItems(p Parameter, tokens ...string) {
+	if p.In == "body" || p.Ref != "" {
+		return
+	}
+
+	if p.Type == "array" && p.Items == nil {
+		v.add(SeverityError, MissingItems, pointer(tokens...), "array parameter, %v, has no items", p.Name)
+	}
+
+	location := tokens
+	for items := p.Items; items != nil; items = items.Items {
+		location = append(location, "items")
+		if items.Type == "array" && items.Items == nil {
+			v.add(SeverityError, MissingItems, pointer(location...), "array items of parameter, %v, have no items", p.Name)
+		}
+	}
+}
+
+// hasFormMediaType returns true if consumes includes either of the form media types
+func hasFormMediaType(consumes []string) bool {
+	for _, mediaType := range consumes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) response(r Response, tokens ...string) {
+	v.ref(r.Ref, tokens...)
+	v.schema(r.Schema, append(tokens, "schema")...)
+}
+
+// Validate checks the api for problems that would make the published spec invalid or misleading.  Problems are
+// returned with their severity and the json pointer of their location; nil is returned if none were found
+func (a *API) Validate() ValidationErrors {
+	a, _ = a.snapshot()
+	v := &validator{api: a}
+
+	tags := map[string]bool{}
+	for _, tag := range a.Tags {
+		tags[tag.Name] = true
+	}
+
+	paths := make([]string, 0, len(a.Paths))
+	for p := range a.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	operationIDs := map[string]string{}
+	for _, p := range paths {
+		endpoints := a.Paths[p]
+		for i, param := range endpoints.Parameters {
+			v.parameter(param, p, "paths", p, "parameters", strconv.Itoa(i))
+		}
+		endpoints.Walk(func(e *Endpoint) {
+			v.endpoint(e, endpoints.Parameters, operationIDs, tags, "paths", p, strings.ToLower(e.Method))
+		})
+	}
+
+	v.security(a.Security)
+
+	names := make([]string, 0, len(a.SecurityDefinitions))
+	for name := range a.SecurityDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := a.SecurityDefinitions[name].Err(); err != nil {
+			v.add(SeverityError, InvalidSecurityScheme, pointer("securityDefinitions", name), "security scheme, %v: %v",
+				name, err)
+		}
+	}
+
+	names = names[:0]
+	for name := range a.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !isValidName(name) {
+			v.add(SeverityError, InvalidName, pointer("definitions", name), "definition name, %q, is invalid", name)
+		}
+		v.object(a.Definitions[name], "definitions", name)
+	}
+
+	names = names[:0]
+	for name := range a.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.parameterItems(a.Parameters[name], "parameters", name)
+		v.schema(a.Parameters[name].Schema, "parameters", name, "schema")
+	}
+
+	names = names[:0]
+	for name := range a.Responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.response(a.Responses[name], "responses", name)
+	}
+
+	return v.errors
+}
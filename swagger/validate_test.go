@@ -0,0 +1,190 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/savaki/swag"
+	"github.com/savaki/swag/endpoint"
+	"github.com/savaki/swag/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, petstore().Validate(), "expected a valid api to have no problems")
+
+	api := swag.New(
+		swag.Tag("pets", "everything about pets"),
+		swag.Endpoints(
+			endpoint.New("get", "/pets/{id}", "find pet",
+				endpoint.OperationID("findPet"),
+				endpoint.Path("petId", "integer", "pet id", true),
+				endpoint.Tags("pets", "store"),
+				endpoint.Security("oauth", "read"),
+			),
+			endpoint.New("delete", "/pets/{id}", "delete pet",
+				endpoint.OperationID("findPet"),
+				endpoint.Path("id", "integer", "pet id", true),
+				endpoint.Response(http.StatusOK, Animal{}, "deleted"),
+			),
+		),
+	)
+	api.Definitions["swagger_testAnimal"].Properties["category"] = swagger.Property{Ref: "#/definitions/Missing"}
+
+	errs := api.Validate()
+	codes := []swagger.ValidationCode{}
+	locations := []string{}
+	for _, err := range errs {
+		codes = append(codes, err.Code)
+		locations = append(locations, err.Location)
+	}
+	assert.Equal(t, []swagger.ValidationCode{
+		swagger.UnknownPathParameter,
+		swagger.MissingPathParameter,
+		swagger.DuplicateOperationID,
+		swagger.UndeclaredTag,
+		swagger.UndefinedSecurityScheme,
+		swagger.MissingDefinition,
+	}, codes)
+	assert.Equal(t, []string{
+		"#/paths/~1pets~1{id}/get/parameters/0",
+		"#/paths/~1pets~1{id}/get",
+		"#/paths/~1pets~1{id}/get/operationId",
+		"#/paths/~1pets~1{id}/get/tags/1",
+		"#/paths/~1pets~1{id}/get/security/0/oauth",
+		"#/definitions/swagger_testAnimal/properties/category/$ref",
+	}, locations)
+
+	assert.Len(t, errs.Errors(), 5)
+	assert.Len(t, errs.Warnings(), 1)
+	assert.Equal(t, swagger.SeverityWarning, errs.Warnings()[0].Severity)
+	assert.Equal(t, "operationId, findPet, is also used by #/paths/~1pets~1{id}/delete/operationId", errs[2].Message)
+	assert.Contains(t, errs.Error(), "error: #/paths/~1pets~1{id}/get: path, /pets/{id}, has no parameter for placeholder, id")
+}
+
+func TestValidateReferences(t *testing.T) {
+	api := swag.New(
+		swag.Parameter("id", swagger.Parameter{In: "path", Name: "id", Type: "integer", Required: true}),
+		swag.Response("Error", Animal{}, "unexpected error"),
+		swag.Endpoints(
+			endpoint.New("get", "/pets/{id}", "find pet",
+				endpoint.ParameterRef("id"),
+				endpoint.ParameterRef("limit"),
+				endpoint.ResponseRef(http.StatusInternalServerError, "Error"),
+				endpoint.ResponseRef(http.StatusNotFound, "NotFound"),
+			),
+		),
+	)
+	api.Responses["Error"].Schema.Ref = "#/definitions/Missing"
+
+	errs := api.Validate()
+	locations := []string{}
+	for _, err := range errs {
+		locations = append(locations, err.Location)
+	}
+	assert.Equal(t, []string{
+		"#/paths/~1pets~1{id}/get/parameters/1/$ref",
+		"#/paths/~1pets~1{id}/get/responses/404/$ref",
+		"#/responses/Error/schema/$ref",
+	}, locations, "expected path parameters to be resolved")
+	assert.Equal(t, swagger.MissingParameter, errs[0].Code)
+	assert.Equal(t, swagger.MissingResponse, errs[1].Code)
+	assert.Equal(t, "response, NotFound, does not exist", errs[1].Message)
+	assert.Equal(t, swagger.MissingDefinition, errs[2].Code)
+}
+
+func TestValidateForm(t *testing.T) {
+	api := &swagger.API{}
+	api.AddEndpoint(&swagger.Endpoint{
+		Method:     "POST",
+		Path:       "/pets",
+		Consumes:   []string{"application/json"},
+		Parameters: []swagger.Parameter{{In: "formData", Name: "name", Type: "string"}},
+	})
+
+	errs := api.Validate()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, swagger.UnconsumedForm, errs[0].Code)
+		assert.Equal(t, "#/paths/~1pets/post/consumes", errs[0].Location)
+	}
+
+	api.AddParameter("name", swagger.Parameter{In: "formData", Name: "name", Type: "string"})
+	api.AddEndpoint(&swagger.Endpoint{
+		Method:     "POST",
+		Path:       "/pets",
+		Consumes:   []string{"application/xml"},
+		Parameters: []swagger.Parameter{{Ref: swagger.MakeParameterRef("name")}},
+	})
+	assert.Equal(t, []string{"application/xml", "application/x-www-form-urlencoded"},
+		api.Paths["/pets"].Post.Consumes, "expected the form media type to be appended to explicit consumes")
+	assert.Nil(t, api.Validate())
+}
+
+func TestValidateItems(t *testing.T) {
+	api := &swagger.API{}
+	api.AddParameter("ids", swagger.Parameter{In: "query", Name: "ids", Type: "array"})
+	api.AddEndpoint(&swagger.Endpoint{
+		Method: "GET",
+		Path:   "/pets",
+		Parameters: []swagger.Parameter{
+			{In: "query", Name: "tags", Type: "array", Items: &swagger.Items{Type: "string"}},
+			{In: "query", Name: "grid", Type: "array", Items: &swagger.Items{Type: "array"}},
+			{Ref: swagger.MakeParameterRef("ids")},
+		},
+	})
+
+	errs := api.Validate()
+	locations := []string{}
+	for _, err := range errs {
+		assert.Equal(t, swagger.MissingItems, err.Code)
+		locations = append(locations, err.Location)
+	}
+	assert.Equal(t, []string{"#/paths/~1pets/get/parameters/1/items", "#/parameters/ids"}, locations)
+}
+
+func TestValidateNames(t *testing.T) {
+	api := &swagger.API{
+		Definitions: map[string]swagger.Object{
+			"":    {Type: "object"},
+			"Pet": {Type: "object"},
+		},
+	}
+
+	errs := api.Validate()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, swagger.InvalidName, errs[0].Code)
+		assert.Equal(t, "#/definitions/", errs[0].Location)
+	}
+}
+
+func TestValidateSecurityScheme(t *testing.T) {
+	scheme := swagger.SecurityScheme{}
+	swagger.APIKeySecurity("token", "cookie")(&scheme)
+
+	api := &swagger.API{
+		SecurityDefinitions: map[string]swagger.SecurityScheme{
+			"basic": {Type: "basic"},
+			"token": scheme,
+		},
+	}
+
+	errs := api.Validate()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, swagger.InvalidSecurityScheme, errs[0].Code)
+		assert.Equal(t, "#/securityDefinitions/token", errs[0].Location)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlKeys lists, for each kind of swagger entity, the conventional order of its keys.  Keys not listed follow in
+// alphabetical order, as do the keys of entities with no conventional order e.g. paths and definitions
+var yamlKeys = map[string][]string{
+	"document": {"swagger", "openapi", "info", "servers", "host", "basePath", "schemes", "consumes", "produces", "paths",
+		"definitions", "components", "parameters", "responses", "securityDefinitions", "security", "tags", "externalDocs"},
+	"info":           {"title", "description", "termsOfService", "contact", "license", "version"},
+	"pathItem":       {"get", "put", "post", "delete", "options", "head", "patch", "trace", "connect", "parameters"},
+	"operation":      {"tags", "summary", "description", "operationId", "consumes", "produces", "parameters", "requestBody", "responses", "deprecated", "security"},
+	"parameter":      {"name", "in", "description", "required", "style", "explode", "schema", "type", "format", "items", "collectionFormat", "default", "enum"},
+	"requestBody":    {"description", "required", "content"},
+	"response":       {"description", "schema", "headers", "content"},
+	"header":         {"description", "type", "format", "schema"},
+	"schema":         {"$ref", "type", "format", "description", "discriminator", "required", "properties", "allOf", "items", "additionalProperties", "enum", "default", "example"},
+	"securityScheme": {"type", "description", "name", "in", "scheme", "flow", "flows", "authorizationUrl", "tokenUrl", "scopes"},
+	"tag":            {"name", "description", "externalDocs"},
+}
+
+// yamlChildren identifies, for each kind of swagger entity, the kind of the entities held by its keys; * matches any
+// key.  Example, default, and enum values are always user data and have no kind
+var yamlChildren = map[string]map[string]string{
+	"document": {"info": "info", "paths": "paths", "definitions": "schemas", "components": "components",
+		"parameters": "parameters", "responses": "responses", "securityDefinitions": "securitySchemes", "tags": "tag"},
+	"components":      {"schemas": "schemas", "parameters": "parameters", "responses": "responses", "securitySchemes": "securitySchemes"},
+	"paths":           {"*": "pathItem"},
+	"pathItem":        {"parameters": "parameter", "*": "operation"},
+	"operation":       {"parameters": "parameter", "requestBody": "requestBody", "responses": "responses"},
+	"parameters":      {"*": "parameter"},
+	"parameter":       {"schema": "schema", "items": "schema"},
+	"requestBody":     {"content": "content"},
+	"responses":       {"*": "response"},
+	"response":        {"schema": "schema", "headers": "headers", "content": "content"},
+	"headers":         {"*": "header"},
+	"header":          {"schema": "schema"},
+	"content":         {"*": "mediaType"},
+	"mediaType":       {"schema": "schema"},
+	"schemas":         {"*": "schema"},
+	"schema":          {"properties": "schemas", "items": "schema", "additionalProperties": "schema", "allOf": "schema"},
+	"securitySchemes": {"*": "securityScheme"},
+}
+
+// yamlChild returns the kind of the entity held by key within an entity of the specified kind
+func yamlChild(kind, key string) string {
+	children := yamlChildren[kind]
+	if child, ok := children[key]; ok {
+		return child
+	}
+	return children["*"]
+}
+
+// yamlKeyOrder sorts keys into the conventional order for the kind of entity
+type yamlKeyOrder struct {
+	keys  []string
+	index map[string]int
+}
+
+func (x yamlKeyOrder) Len() int      { return len(x.keys) }
+func (x yamlKeyOrder) Swap(i, j int) { x.keys[i], x.keys[j] = x.keys[j], x.keys[i] }
+func (x yamlKeyOrder) Less(i, j int) bool {
+	a, aok := x.index[x.keys[i]]
+	b, bok := x.index[x.keys[j]]
+	switch {
+	case aok && bok:
+		return a < b
+	case aok || bok:
+		return aok
+	default:
+		return x.keys[i] < x.keys[j]
+	}
+}
+
+// yamlValue converts the decoded json value, v, of the specified kind into a value whose keys yaml.v2 encodes in the
+// conventional order
+func yamlValue(v interface{}, kind string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		order := yamlKeyOrder{index: map[string]int{}}
+		for i, key := range yamlKeys[kind] {
+			order.index[key] = i
+		}
+		for key := range value {
+			order.keys = append(order.keys, key)
+		}
+		sort.Sort(order)
+
+		m := make(yaml.MapSlice, 0, len(value))
+		for _, key := range order.keys {
+			m = append(m, yaml.MapItem{Key: key, Value: yamlValue(value[key], yamlChild(kind, key))})
+		}
+		return m
+
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = yamlValue(item, kind)
+		}
+		return items
+
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+
+	default:
+		return value
+	}
+}
+
+// yamlDocument converts the swagger or OpenAPI document, doc, into a value yaml.v2 encodes in the conventional order
+func yamlDocument(doc interface{}) (interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return yamlValue(v, "document"), nil
+}
+
+// MarshalYAML implements yaml.Marshaler; keys are encoded in the conventional swagger order rather than the order of
+// the go fields
+func (a *API) MarshalYAML() (interface{}, error) {
+	return yamlDocument(a)
+}
+
+// YAML encodes the api as a swagger 2.0 yaml document
+func (a *API) YAML() ([]byte, error) {
+	return yaml.Marshal(a)
+}
+
+// MarshalYAML implements yaml.Marshaler; keys are encoded in the conventional OpenAPI order rather than the order of
+// the go fields
+func (o *OpenAPI) MarshalYAML() (interface{}, error) {
+	return yamlDocument(o)
+}
+
+// YAML encodes the document as an OpenAPI 3.0 yaml document
+func (o *OpenAPI) YAML() ([]byte, error) {
+	return yaml.Marshal(o)
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package swagger_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/savaki/swag/swagger"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+// topLevelKeys returns the keys of the yaml document, data, in the order they appear
+func topLevelKeys(data []byte) []string {
+	return regexp.MustCompile(`(?m)^([a-zA-Z]+):`).FindAllString(string(data), -1)
+}
+
+func TestYAML(t *testing.T) {
+	api := petstore()
+
+	data, err := api.YAML()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"swagger:", "info:", "host:", "basePath:", "schemes:", "paths:", "definitions:",
+		"securityDefinitions:", "tags:"}, topLevelKeys(data))
+
+	text := string(data)
+	assert.True(t, strings.Index(text, "title:") < strings.Index(text, "version:"), "expected info in conventional order")
+	assert.True(t, strings.Index(text, "\n  /pet:") < strings.Index(text, "\n  /pet/{petId}:"), "expected paths in alphabetical order")
+	assert.Contains(t, text, "format: int32", "expected integers to remain unquoted")
+
+	// the yaml document must describe the same api as the json document
+	parsed, err := swagger.ParseYAML(data)
+	assert.Nil(t, err)
+	expected, _ := json.Marshal(api)
+	actual, _ := json.Marshal(parsed)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestOpenAPIYAML(t *testing.T) {
+	data, err := petstore().OpenAPI().YAML()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"openapi:", "info:", "servers:", "paths:", "components:"}, topLevelKeys(data))
+}
+
+func TestHandlerYAML(t *testing.T) {
+	testCases := map[string]struct {
+		URL    string
+		Accept string
+		YAML   bool
+	}{
+		"json":           {URL: "http://localhost/swagger.json"},
+		"yaml extension": {URL: "http://localhost/swagger.yaml", YAML: true},
+		"yml extension":  {URL: "http://localhost/swagger.yml", YAML: true},
+		"accept yaml":    {URL: "http://localhost/swagger", Accept: "application/x-yaml", YAML: true},
+		"accept order":   {URL: "http://localhost/swagger", Accept: "application/json, application/yaml;q=0.9"},
+		"accept any":     {URL: "http://localhost/swagger", Accept: "*/*"},
+		"accept quality": {URL: "http://localhost/swagger", Accept: "application/json;q=0.1, application/yaml", YAML: true},
+		"reject yaml":    {URL: "http://localhost/swagger", Accept: "application/yaml;q=0"},
+	}
+
+	h := petstore().Handler(false)
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			req.Header.Set("Accept", tc.Accept)
+			w := httptest.NewRecorder()
+			h(w, req)
+
+			doc := map[string]interface{}{}
+			if tc.YAML {
+				assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+				assert.Nil(t, yaml.Unmarshal(w.Body.Bytes(), &doc))
+			} else {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &doc))
+			}
+			assert.Equal(t, "2.0", doc["swagger"])
+			assert.Equal(t, "localhost", doc["host"])
+		})
+	}
+}
+
+func TestHandlerError(t *testing.T) {
+	api := petstore()
+	api.Definitions["swagger_testAnimal"].Properties["name"] = swagger.Property{Type: "string", Example: func() {}}
+
+	for _, u := range []string{"http://localhost/swagger.json", "http://localhost/swagger.yaml"} {
+		req, _ := http.NewRequest(http.MethodGet, u, nil)
+		w := httptest.NewRecorder()
+		api.Handler(false)(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code, u)
+	}
+}